@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adjustCmd = &cobra.Command{
+		Use:   "adjust",
+		Short: "Adjust user balance",
+		Long:  `Credit or debit user's balance manually with a reason code and a note.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, err := decimal.NewFromString(AdjustAmount)
+			if err != nil {
+				return err
+			}
+
+			adjustment := models.Adjustment{
+				Login:    AdjustLogin,
+				Amount:   amount,
+				Reason:   AdjustReason,
+				Note:     AdjustNote,
+				Operator: AdjustOperator,
+			}
+
+			if err := adjustment.ValidateFields(); err != nil {
+				return err
+			}
+
+			return adjust(cmd.Context(), cmd.OutOrStdout(), &adjustment)
+		},
+	}
+	AdjustLogin    string
+	AdjustAmount   string
+	AdjustReason   string
+	AdjustNote     string
+	AdjustOperator string
+)
+
+func init() {
+	adjustCmd.Flags().StringVar(&AdjustLogin, "login", "",
+		"User login to adjust balance for")
+
+	adjustCmd.Flags().StringVar(&AdjustAmount, "amount", "",
+		"Signed amount of points, negative amount debits the user")
+
+	adjustCmd.Flags().StringVar(&AdjustReason, "reason", "",
+		"Reason code: GOODWILL|COMPENSATION|CORRECTION|FRAUD")
+
+	adjustCmd.Flags().StringVar(&AdjustNote, "note", "",
+		"Free-text note explaining the adjustment")
+
+	adjustCmd.Flags().StringVar(&AdjustOperator, "operator", "",
+		"Login of the operator making the adjustment")
+
+	for _, flag := range []string{"login", "amount", "reason", "note", "operator"} {
+		_ = adjustCmd.MarkFlagRequired(flag)
+	}
+
+	rootCmd.AddCommand(adjustCmd)
+}
+
+func adjust(ctx context.Context, w io.Writer, adjustment *models.Adjustment) error {
+	ordersStore, err := openOrdersStore()
+	if err != nil {
+		return err
+	}
+	defer ordersStore.Close()
+
+	err = ordersStore.Adjust(ctx, adjustment)
+	if err != nil {
+		return err
+	}
+
+	return models.Encode(adjustment, w)
+}
@@ -11,10 +11,15 @@ import (
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
+	startServer, err := cmd.Execute()
+	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to parse command line arguments")
 	}
 
+	if !startServer {
+		return
+	}
+
 	LoyaltyServerConfig := server.Config{
 		ServerAddress: cmd.ServerAddress,
 		LogLevel:      cmd.LogLevel,
@@ -24,6 +29,19 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to parse environment variables")
 	}
 
+	if err := LoyaltyServerConfig.ValidateFields(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse environment variables")
+	}
+
+	fraudRules := LoyaltyServerConfig.FraudRules()
+	if err := fraudRules.ValidateFields(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse fraud rules")
+	}
+
+	if err := LoyaltyServerConfig.PartnerKeys.ValidatePartners(LoyaltyServerConfig.OrderValidators); err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse partner keys")
+	}
+
 	logging.Level(LoyaltyServerConfig.LogLevel)
 
 	loyaltyServer := server.LoyaltyServer{Cfg: &LoyaltyServerConfig}
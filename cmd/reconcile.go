@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/caarlos0/env/v6"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/server"
+	"github.com/spf13/cobra"
+)
+
+const (
+	psqlDriverName = "pgx"
+
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+var (
+	reconcileCmd = &cobra.Command{
+		Use:   "reconcile",
+		Short: "Reconcile users balances",
+		Long: `Recompute every user's balance from orders and ledger, compare it with
+the materialized balance and report the differences.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ReconcileFormat != formatJSON && ReconcileFormat != formatCSV {
+				return fmt.Errorf("%w: --format", ErrInvalidParam)
+			}
+
+			return reconcile(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+	ReconcileFormat string
+	ReconcileRepair bool
+)
+
+func init() {
+	reconcileCmd.Flags().StringVarP(&ReconcileFormat, "format", "f", formatJSON,
+		"Report format: json|csv")
+
+	reconcileCmd.Flags().BoolVar(&ReconcileRepair, "repair", false,
+		"Repair materialized balances in a transaction")
+
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+func reconcile(ctx context.Context, w io.Writer) error {
+	ordersStore, err := openOrdersStore()
+	if err != nil {
+		return err
+	}
+	defer ordersStore.Close()
+
+	discrepancies, err := ordersStore.Reconcile(ctx, ReconcileRepair)
+	if err != nil {
+		return err
+	}
+
+	return writeDiscrepancies(w, ReconcileFormat, discrepancies)
+}
+
+func writeDiscrepancies(w io.Writer, format string, discrepancies []models.Discrepancy) error {
+	if format == formatJSON {
+		return models.Encode(discrepancies, w)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"login", "expected", "materialized", "difference"}); err != nil {
+		return err
+	}
+
+	for _, d := range discrepancies {
+		err := csvWriter.Write([]string{d.Login, d.Expected.String(), d.Materialized.String(), d.Difference.String()})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// openOrdersStore connects to the database set by flag or DATABASE_URI
+// environment variable, store settings are taken from the environment.
+func openOrdersStore() (*orders.DBStore, error) {
+	cfg := server.Config{
+		DatabaseURI: DatabaseURI,
+	}
+
+	if err := env.Parse(&cfg); err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open(psqlDriverName, cfg.DatabaseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders.NewDBStore(conn, cfg.OrdersConfig()), nil
+}
@@ -39,7 +39,7 @@ func init() {
 	rootCmd.Flags().StringVarP(&ServerAddress, "address", "a", defaultServerAddress,
 		"Pair of ip:port to listen on")
 
-	rootCmd.Flags().StringVarP(&DatabaseURI, "databaseURI", "d", "",
+	rootCmd.PersistentFlags().StringVarP(&DatabaseURI, "databaseURI", "d", "",
 		"Database URI for loyalty store")
 
 	rootCmd.Flags().StringVarP(&AccrualAddress, "accrualAddress", "r", defaultAccrualAddress,
@@ -49,6 +49,10 @@ func init() {
 		"Set log level: DEBUG|INFO|WARNING|ERROR")
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the requested command and reports whether
+// the server should be started.
+func Execute() (bool, error) {
+	command, err := rootCmd.ExecuteC()
+
+	return command == rootCmd, err
 }
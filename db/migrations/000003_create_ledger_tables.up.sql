@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS accrual_lots(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    source VARCHAR (255),
+    amount DECIMAL NOT NULL,
+    remaining DECIMAL NOT NULL,
+    accrued_at TIMESTAMP DEFAULT now(),
+    expires_at TIMESTAMP DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS accrual_lots_login_idx ON accrual_lots (login, accrued_at);
+
+CREATE TABLE IF NOT EXISTS ledger(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    kind VARCHAR (50) NOT NULL,
+    amount DECIMAL NOT NULL,
+    reference VARCHAR (255),
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS ledger_login_idx ON ledger (login, created_at);
+
+INSERT INTO accrual_lots (login, source, amount, remaining, accrued_at)
+SELECT o.login, o.number::text, o.accrual,
+       GREATEST(0, LEAST(o.accrual,
+           SUM(o.accrual) OVER (PARTITION BY o.login ORDER BY o.uploaded_at, o.number) - COALESCE(w.withdrawn, 0))),
+       o.uploaded_at
+FROM orders o
+LEFT JOIN (SELECT login, SUM(withdraw) AS withdrawn FROM orders WHERE withdraw IS NOT NULL GROUP BY login) w
+    ON w.login = o.login
+WHERE o.status = 'PROCESSED' AND o.withdraw IS NULL AND o.accrual IS NOT NULL;
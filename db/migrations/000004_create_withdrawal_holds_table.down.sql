@@ -0,0 +1 @@
+DROP TABLE IF EXISTS withdrawal_holds;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS withdrawal_holds(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    number BIGINT NOT NULL,
+    sum DECIMAL NOT NULL,
+    status VARCHAR (50) DEFAULT 'AUTHORIZED',
+    created_at TIMESTAMP DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS withdrawal_holds_login_idx ON withdrawal_holds (login, status);
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processed_at TIMESTAMP DEFAULT NULL;
+UPDATE orders SET processed_at = uploaded_at WHERE status = 'PROCESSED' AND withdraw IS NULL;
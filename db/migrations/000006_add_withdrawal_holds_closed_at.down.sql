@@ -0,0 +1 @@
+ALTER TABLE withdrawal_holds DROP COLUMN IF EXISTS closed_at;
\ No newline at end of file
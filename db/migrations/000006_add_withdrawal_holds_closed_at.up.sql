@@ -0,0 +1,5 @@
+ALTER TABLE withdrawal_holds ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP DEFAULT NULL;
+UPDATE withdrawal_holds SET closed_at = expires_at WHERE status = 'EXPIRED';
+UPDATE withdrawal_holds h SET closed_at = o.uploaded_at FROM orders o
+    WHERE h.status = 'CAPTURED' AND o.number = h.number AND o.withdraw IS NOT NULL;
+UPDATE withdrawal_holds SET closed_at = created_at WHERE status <> 'AUTHORIZED' AND closed_at IS NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS adjustments(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    amount DECIMAL NOT NULL,
+    reason VARCHAR (50) NOT NULL,
+    note TEXT NOT NULL,
+    operator VARCHAR (50) NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS adjustments_login_idx ON adjustments (login, created_at);
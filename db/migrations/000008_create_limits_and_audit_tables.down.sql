@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS withdrawal_limits;
+DROP TABLE IF EXISTS audit_events;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS audit_events(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50),
+    actor VARCHAR (50),
+    action VARCHAR (50) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS audit_events_login_idx ON audit_events (login, created_at);
+
+CREATE TABLE IF NOT EXISTS withdrawal_limits(
+    login VARCHAR (50) REFERENCES users(login),
+    period VARCHAR (50) NOT NULL,
+    max_count INTEGER NOT NULL DEFAULT 0,
+    max_amount DECIMAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (login, period)
+);
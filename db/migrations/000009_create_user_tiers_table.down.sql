@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_tiers;
\ No newline at end of file
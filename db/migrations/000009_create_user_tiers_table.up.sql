@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_tiers(
+    login VARCHAR (50) PRIMARY KEY REFERENCES users(login),
+    tier VARCHAR (50) NOT NULL,
+    evaluated_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_activity_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS user_tiers_activity_idx ON user_tiers (last_activity_at);
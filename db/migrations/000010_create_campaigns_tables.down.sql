@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS campaign_bonuses;
+DROP TABLE IF EXISTS campaign_users;
+DROP TABLE IF EXISTS campaigns;
\ No newline at end of file
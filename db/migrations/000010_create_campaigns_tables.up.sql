@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS campaigns(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR (100) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    multiplier DECIMAL,
+    bonus DECIMAL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS campaigns_window_idx ON campaigns (starts_at, ends_at);
+CREATE TABLE IF NOT EXISTS campaign_users(
+    campaign_id INTEGER REFERENCES campaigns(id),
+    login VARCHAR (50) REFERENCES users(login),
+    PRIMARY KEY (campaign_id, login)
+);
+CREATE TABLE IF NOT EXISTS campaign_bonuses(
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER REFERENCES campaigns(id),
+    login VARCHAR (50) NOT NULL,
+    number VARCHAR (50) NOT NULL,
+    amount DECIMAL NOT NULL,
+    reversed_amount DECIMAL,
+    created_at TIMESTAMP DEFAULT now(),
+    reversed_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS campaign_bonuses_campaign_idx ON campaign_bonuses (campaign_id);
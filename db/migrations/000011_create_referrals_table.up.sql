@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR (20) UNIQUE;
+UPDATE users SET referral_code = upper(substr(md5(login || random()::text), 1, 8)) WHERE referral_code IS NULL;
+CREATE TABLE IF NOT EXISTS referrals(
+    id SERIAL PRIMARY KEY,
+    referrer VARCHAR (50) NOT NULL REFERENCES users(login),
+    referee VARCHAR (50) NOT NULL UNIQUE REFERENCES users(login),
+    status VARCHAR (50) NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    rewarded_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS referrals_referrer_idx ON referrals (referrer);
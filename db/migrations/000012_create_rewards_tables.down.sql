@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS redemptions;
+DROP TABLE IF EXISTS rewards;
\ No newline at end of file
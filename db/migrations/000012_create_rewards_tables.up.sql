@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS rewards(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR (100) NOT NULL,
+    kind VARCHAR (50) NOT NULL,
+    description TEXT,
+    price DECIMAL NOT NULL,
+    stock INTEGER,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS redemptions(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    reward_id INTEGER REFERENCES rewards(id),
+    price DECIMAL NOT NULL,
+    code VARCHAR (20) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS redemptions_login_idx ON redemptions (login, created_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS audit_events_action_idx;
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_codes;
+DROP TABLE IF EXISTS promo_batches;
\ No newline at end of file
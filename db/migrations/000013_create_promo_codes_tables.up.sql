@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS promo_batches(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR (100) NOT NULL,
+    amount DECIMAL NOT NULL,
+    max_uses INTEGER NOT NULL DEFAULT 1,
+    expires_at TIMESTAMP,
+    created_by VARCHAR (50) NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS promo_codes(
+    code VARCHAR (50) PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES promo_batches(id),
+    uses INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS promo_codes_batch_idx ON promo_codes (batch_id);
+CREATE TABLE IF NOT EXISTS promo_redemptions(
+    code VARCHAR (50) REFERENCES promo_codes(code),
+    login VARCHAR (50) REFERENCES users(login),
+    created_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (code, login)
+);
+CREATE INDEX IF NOT EXISTS audit_events_action_idx ON audit_events (login, action, created_at);
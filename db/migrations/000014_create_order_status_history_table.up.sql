@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS order_status_history(
+    id SERIAL PRIMARY KEY,
+    number BIGINT NOT NULL,
+    old_status VARCHAR (50),
+    new_status VARCHAR (50) NOT NULL,
+    accrual DECIMAL,
+    source VARCHAR (50) NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS order_status_history_number_idx ON order_status_history (number, id);
+INSERT INTO order_status_history (number, old_status, new_status, source, created_at)
+SELECT number, NULL, 'NEW', 'USER', uploaded_at FROM orders WHERE withdraw IS NULL;
+INSERT INTO order_status_history (number, old_status, new_status, accrual, source, created_at)
+SELECT number, 'NEW', status, accrual, 'ACCRUAL', COALESCE(processed_at, uploaded_at)
+FROM orders WHERE withdraw IS NULL AND status <> 'NEW';
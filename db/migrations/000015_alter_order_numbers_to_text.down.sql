@@ -0,0 +1,4 @@
+ALTER TABLE order_status_history ALTER COLUMN number TYPE BIGINT USING number::bigint;
+ALTER TABLE withdrawal_holds ALTER COLUMN number TYPE BIGINT USING number::bigint;
+ALTER TABLE orders DROP COLUMN IF EXISTS partner;
+ALTER TABLE orders ALTER COLUMN number TYPE BIGINT USING number::bigint;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE orders ALTER COLUMN number TYPE TEXT USING number::text;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS partner VARCHAR (50) DEFAULT NULL;
+ALTER TABLE withdrawal_holds ALTER COLUMN number TYPE TEXT USING number::text;
+ALTER TABLE order_status_history ALTER COLUMN number TYPE TEXT USING number::text;
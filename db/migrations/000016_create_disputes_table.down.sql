@@ -0,0 +1,2 @@
+ALTER TABLE order_status_history DROP COLUMN IF EXISTS note;
+DROP TABLE IF EXISTS disputes;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS disputes(
+    id SERIAL PRIMARY KEY,
+    number TEXT NOT NULL,
+    login VARCHAR (50) REFERENCES users(login),
+    comment TEXT NOT NULL,
+    status VARCHAR (50) NOT NULL DEFAULT 'OPEN',
+    assignee VARCHAR (50),
+    action VARCHAR (50),
+    resolution_comment TEXT,
+    adjustment_id INTEGER REFERENCES adjustments(id),
+    operator VARCHAR (50),
+    created_at TIMESTAMP DEFAULT now(),
+    resolved_at TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS disputes_number_unresolved_idx ON disputes (number) WHERE status <> 'RESOLVED';
+CREATE INDEX IF NOT EXISTS disputes_status_idx ON disputes (status, id);
+ALTER TABLE order_status_history ADD COLUMN IF NOT EXISTS note TEXT;
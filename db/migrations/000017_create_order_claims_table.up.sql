@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS order_claims(
+    id SERIAL PRIMARY KEY,
+    number TEXT NOT NULL,
+    claimant VARCHAR (50) REFERENCES users(login),
+    owner VARCHAR (50) REFERENCES users(login),
+    proof TEXT,
+    status VARCHAR (50) NOT NULL DEFAULT 'PENDING',
+    contest_comment TEXT,
+    decider VARCHAR (50),
+    deadline TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    decided_at TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS order_claims_number_open_idx ON order_claims (number)
+    WHERE status IN ('PENDING', 'CONTESTED');
+CREATE INDEX IF NOT EXISTS order_claims_deadline_idx ON order_claims (deadline) WHERE status = 'PENDING';
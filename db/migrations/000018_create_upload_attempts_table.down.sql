@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS flag_reason;
+DROP TABLE IF EXISTS upload_attempts;
\ No newline at end of file
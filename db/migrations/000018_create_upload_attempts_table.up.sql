@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS upload_attempts(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    ip VARCHAR (64) NOT NULL,
+    number TEXT NOT NULL,
+    valid BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS upload_attempts_login_idx ON upload_attempts (login, created_at);
+CREATE INDEX IF NOT EXISTS upload_attempts_ip_idx ON upload_attempts (ip, created_at);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS flag_reason TEXT;
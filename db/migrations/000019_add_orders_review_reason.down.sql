@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS orders_review_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS review_reason;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS review_reason TEXT;
+CREATE INDEX IF NOT EXISTS orders_review_idx ON orders (uploaded_at) WHERE status = 'REVIEW';
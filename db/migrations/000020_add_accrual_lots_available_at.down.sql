@@ -0,0 +1 @@
+ALTER TABLE accrual_lots DROP COLUMN IF EXISTS available_at;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE accrual_lots ADD COLUMN IF NOT EXISTS available_at TIMESTAMP DEFAULT NULL;
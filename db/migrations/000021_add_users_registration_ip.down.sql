@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS registration_ip;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS registration_ip VARCHAR (64);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS promo_attempts;
\ No newline at end of file
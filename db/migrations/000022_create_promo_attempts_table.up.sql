@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS promo_attempts(
+    id SERIAL PRIMARY KEY,
+    login VARCHAR (50) REFERENCES users(login),
+    ip VARCHAR (64) NOT NULL,
+    succeeded BOOLEAN,
+    created_at TIMESTAMP DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS promo_attempts_login_idx ON promo_attempts (login, created_at);
+CREATE INDEX IF NOT EXISTS promo_attempts_ip_idx ON promo_attempts (ip, created_at);
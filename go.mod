@@ -7,7 +7,6 @@ require (
 	github.com/go-chi/chi/v5 v5.0.4
 	github.com/go-chi/jwtauth/v5 v5.0.2
 	github.com/go-rfe/logging v0.1.0
-	github.com/go-rfe/utils v0.1.0
 	github.com/golang/mock v1.6.0
 	github.com/jackc/pgconn v1.11.0
 	github.com/jackc/pgx/v4 v4.15.0
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ReasonGoodwill     = "GOODWILL"
+	ReasonCompensation = "COMPENSATION"
+	ReasonCorrection   = "CORRECTION"
+	ReasonFraud        = "FRAUD"
+)
+
+var (
+	ErrInvalidAdjustmentAmount = errors.New("adjustment amount must not be zero")
+	ErrInvalidReason           = errors.New("reason code is invalid")
+	ErrEmptyNote               = errors.New("note is required")
+	ErrEmptyOperator           = errors.New("operator is required")
+)
+
+var reasonCodes = map[string]struct{}{
+	ReasonGoodwill:     {},
+	ReasonCompensation: {},
+	ReasonCorrection:   {},
+	ReasonFraud:        {},
+}
+
+type Adjustment struct {
+	ID        int64           `json:"id"`
+	Login     string          `json:"login"`
+	Amount    decimal.Decimal `json:"amount"`
+	Reason    string          `json:"reason"`
+	Note      string          `json:"note"`
+	Operator  string          `json:"operator"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (a *Adjustment) ValidateFields() error {
+	if a.Amount.IsZero() {
+		return ErrInvalidAdjustmentAmount
+	}
+
+	if _, ok := reasonCodes[a.Reason]; !ok {
+		return ErrInvalidReason
+	}
+
+	if a.Note == "" {
+		return ErrEmptyNote
+	}
+
+	if a.Operator == "" {
+		return ErrEmptyOperator
+	}
+
+	return nil
+}
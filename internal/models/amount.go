@@ -0,0 +1,62 @@
+package models
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// AmountError is a violation of the amount policy, Code tells violations apart.
+type AmountError struct {
+	Code    string
+	Message string
+}
+
+func (e *AmountError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrAmountNotPositive   = &AmountError{Code: "AMOUNT_NOT_POSITIVE", Message: "amount must be positive"}
+	ErrAmountBelowMinimum  = &AmountError{Code: "AMOUNT_BELOW_MINIMUM", Message: "amount is below minimum"}
+	ErrAmountTooPrecise    = &AmountError{Code: "AMOUNT_TOO_PRECISE", Message: "amount has too many decimal places"}
+	ErrAmountAboveMaximum  = &AmountError{Code: "AMOUNT_ABOVE_MAXIMUM", Message: "amount is above per-transaction maximum"}
+	ErrDailyAmountExceeded = &AmountError{Code: "DAILY_AMOUNT_EXCEEDED", Message: "daily maximum is exceeded"}
+)
+
+// AmountPolicy restricts amounts of debit operations. Zero Min, MaxPerTransaction
+// and MaxDaily mean no limit, negative MaxDecimalPlaces allows any precision.
+type AmountPolicy struct {
+	Min               decimal.Decimal
+	MaxDecimalPlaces  int32
+	MaxPerTransaction decimal.Decimal
+	MaxDaily          decimal.Decimal
+}
+
+// Validate checks the amount of a single operation.
+func (p *AmountPolicy) Validate(amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return ErrAmountNotPositive
+	}
+
+	if amount.LessThan(p.Min) {
+		return ErrAmountBelowMinimum
+	}
+
+	if p.MaxDecimalPlaces >= 0 && !amount.Equal(amount.Truncate(p.MaxDecimalPlaces)) {
+		return ErrAmountTooPrecise
+	}
+
+	if p.MaxPerTransaction.IsPositive() && amount.GreaterThan(p.MaxPerTransaction) {
+		return ErrAmountAboveMaximum
+	}
+
+	return nil
+}
+
+// ValidateDaily checks the amount against the sum already spent today.
+func (p *AmountPolicy) ValidateDaily(spent decimal.Decimal, amount decimal.Decimal) error {
+	if p.MaxDaily.IsPositive() && spent.Add(amount).GreaterThan(p.MaxDaily) {
+		return ErrDailyAmountExceeded
+	}
+
+	return nil
+}
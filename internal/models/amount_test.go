@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmountPolicy(t *testing.T) {
+	policy := models.AmountPolicy{
+		Min:               decimal.NewFromInt(10),
+		MaxDecimalPlaces:  2,
+		MaxPerTransaction: decimal.NewFromInt(1000),
+		MaxDaily:          decimal.NewFromInt(1500),
+	}
+
+	tests := []struct {
+		name   string
+		amount string
+		spent  string
+		want   error
+	}{
+		{name: "Valid amount", amount: "100.25", spent: "0"},
+		{name: "Trailing zeros", amount: "100.2500", spent: "0"},
+		{name: "Zero amount", amount: "0", spent: "0", want: models.ErrAmountNotPositive},
+		{name: "Negative amount", amount: "-100", spent: "0", want: models.ErrAmountNotPositive},
+		{name: "Below minimum", amount: "9.99", spent: "0", want: models.ErrAmountBelowMinimum},
+		{name: "Too precise", amount: "100.001", spent: "0", want: models.ErrAmountTooPrecise},
+		{name: "Above maximum", amount: "1000.01", spent: "0", want: models.ErrAmountAboveMaximum},
+		{name: "Daily maximum", amount: "600", spent: "1000", want: models.ErrDailyAmountExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := decimal.RequireFromString(tt.amount)
+
+			err := policy.Validate(amount)
+			if err == nil {
+				err = policy.ValidateDaily(decimal.RequireFromString(tt.spent), amount)
+			}
+
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
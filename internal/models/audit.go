@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	AuditWithdrawalLimitExceeded = "WITHDRAWAL_LIMIT_EXCEEDED"
+	AuditWithdrawalLimitsChanged = "WITHDRAWAL_LIMITS_CHANGED"
+	AuditPromoCodeFailed         = "PROMO_CODE_FAILED"
+	AuditOrderCancelled          = "ORDER_CANCELLED"
+	AuditOrderClaimed            = "ORDER_CLAIMED"
+	AuditOrderClaimTransferred   = "ORDER_CLAIM_TRANSFERRED"
+	AuditFraudDecision           = "FRAUD_DECISION"
+	AuditReviewApproved          = "REVIEW_APPROVED"
+	AuditReviewRejected          = "REVIEW_REJECTED"
+)
+
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	Login     string    `json:"login"`
+	Actor     string    `json:"actor,omitempty"`
+	Action    string    `json:"action"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrEmptyCampaignName    = errors.New("campaign name is required")
+	ErrInvalidCampaignDates = errors.New("campaign must end after it starts")
+	ErrInvalidCampaignBoost = errors.New("campaign needs a multiplier above 1 or a positive bonus")
+)
+
+// Campaign boosts accruals of the orders processed within its time window,
+// it targets everyone unless Logins are given.
+type Campaign struct {
+	ID         int64            `json:"id"`
+	Name       string           `json:"name"`
+	StartsAt   time.Time        `json:"starts_at"`
+	EndsAt     time.Time        `json:"ends_at"`
+	Multiplier *decimal.Decimal `json:"multiplier,omitempty"`
+	Bonus      *decimal.Decimal `json:"bonus,omitempty"`
+	Logins     []string         `json:"logins,omitempty"`
+}
+
+func (c *Campaign) ValidateFields() error {
+	if c.Name == "" {
+		return ErrEmptyCampaignName
+	}
+
+	if !c.EndsAt.After(c.StartsAt) {
+		return ErrInvalidCampaignDates
+	}
+
+	one := decimal.NewFromInt(1)
+	if c.Multiplier != nil && !c.Multiplier.GreaterThan(one) || c.Bonus != nil && !c.Bonus.IsPositive() {
+		return ErrInvalidCampaignBoost
+	}
+
+	if c.Multiplier == nil && c.Bonus == nil {
+		return ErrInvalidCampaignBoost
+	}
+
+	return nil
+}
+
+// BonusFor returns the points the campaign adds to the accrual.
+func (c *Campaign) BonusFor(accrual decimal.Decimal) decimal.Decimal {
+	bonus := decimal.Zero
+
+	if c.Multiplier != nil {
+		bonus = bonus.Add(accrual.Mul(*c.Multiplier).Sub(accrual))
+	}
+
+	if c.Bonus != nil {
+		bonus = bonus.Add(*c.Bonus)
+	}
+
+	return bonus
+}
+
+type CampaignReport struct {
+	CampaignID int64           `json:"campaign_id"`
+	Bonuses    int             `json:"bonuses"`
+	Users      int             `json:"users"`
+	Total      decimal.Decimal `json:"total"`
+	Reversed   decimal.Decimal `json:"reversed"`
+}
@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaign(t *testing.T) {
+	start := time.Date(2022, 5, 7, 0, 0, 0, 0, time.UTC)
+	double := decimal.NewFromInt(2)
+	flat := decimal.NewFromInt(50)
+	none := decimal.NewFromInt(1)
+
+	tests := []struct {
+		name     string
+		campaign models.Campaign
+		bonus    string
+		want     error
+	}{
+		{
+			name:     "Double points",
+			campaign: models.Campaign{Name: "weekend", StartsAt: start, EndsAt: start.Add(48 * time.Hour), Multiplier: &double},
+			bonus:    "100",
+		},
+		{
+			name: "Double points with flat bonus",
+			campaign: models.Campaign{
+				Name: "weekend", StartsAt: start, EndsAt: start.Add(48 * time.Hour), Multiplier: &double, Bonus: &flat,
+			},
+			bonus: "150",
+		},
+		{
+			name:     "Ends before start",
+			campaign: models.Campaign{Name: "weekend", StartsAt: start, EndsAt: start, Bonus: &flat},
+			bonus:    "50",
+			want:     models.ErrInvalidCampaignDates,
+		},
+		{
+			name:     "No boost",
+			campaign: models.Campaign{Name: "weekend", StartsAt: start, EndsAt: start.Add(time.Hour), Multiplier: &none},
+			bonus:    "0",
+			want:     models.ErrInvalidCampaignBoost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.campaign.ValidateFields(), tt.want)
+			assert.Equal(t, tt.bonus, tt.campaign.BonusFor(decimal.NewFromInt(100)).String())
+		})
+	}
+}
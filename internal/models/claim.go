@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ClaimPending   = "PENDING"
+	ClaimContested = "CONTESTED"
+	ClaimApproved  = "APPROVED"
+	ClaimRejected  = "REJECTED"
+)
+
+var (
+	ErrEmptyContestComment = errors.New("contest comment is required")
+	ErrInvalidClaimStatus  = errors.New("claim status is invalid")
+)
+
+var claimStatuses = map[string]struct{}{
+	ClaimPending:   {},
+	ClaimContested: {},
+	ClaimApproved:  {},
+	ClaimRejected:  {},
+}
+
+// Claim asks to move the order uploaded by Owner to Claimant. Unless the
+// owner contests it before Deadline the order is moved automatically.
+type Claim struct {
+	ID             int64      `json:"id"`
+	Number         string     `json:"number"`
+	Claimant       string     `json:"claimant,omitempty"`
+	Owner          string     `json:"owner,omitempty"`
+	Proof          string     `json:"proof,omitempty"`
+	Status         string     `json:"status"`
+	ContestComment string     `json:"contest_comment,omitempty"`
+	Decider        string     `json:"decider,omitempty"`
+	Deadline       time.Time  `json:"deadline"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
+
+// ValidateClaimStatus checks the status filter, empty one selects all claims.
+func ValidateClaimStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+
+	if _, ok := claimStatuses[status]; !ok {
+		return ErrInvalidClaimStatus
+	}
+
+	return nil
+}
+
+type ClaimContest struct {
+	Comment string `json:"comment"`
+}
+
+func (c *ClaimContest) ValidateFields() error {
+	if c.Comment == "" {
+		return ErrEmptyContestComment
+	}
+
+	return nil
+}
+
+// ClaimDecision is the admin verdict on the claim, approved claims move the order.
+type ClaimDecision struct {
+	Approve bool `json:"approve"`
+}
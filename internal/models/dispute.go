@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DisputeOpen     = "OPEN"
+	DisputeAssigned = "ASSIGNED"
+	DisputeResolved = "RESOLVED"
+)
+
+const (
+	ResolutionReject = "REJECT"
+	ResolutionRepoll = "REPOLL"
+	ResolutionAdjust = "ADJUST"
+)
+
+var (
+	ErrEmptyDisputeComment     = errors.New("dispute comment is required")
+	ErrInvalidDisputeStatus    = errors.New("dispute status is invalid")
+	ErrInvalidResolutionAction = errors.New("resolution action is invalid")
+)
+
+var disputeStatuses = map[string]struct{}{
+	DisputeOpen:     {},
+	DisputeAssigned: {},
+	DisputeResolved: {},
+}
+
+var resolutionActions = map[string]struct{}{
+	ResolutionReject: {},
+	ResolutionRepoll: {},
+	ResolutionAdjust: {},
+}
+
+// Dispute is the user complaint about the order status or accrual.
+type Dispute struct {
+	ID         int64              `json:"id"`
+	Number     string             `json:"number"`
+	Login      string             `json:"login"`
+	Comment    string             `json:"comment"`
+	Status     string             `json:"status"`
+	Assignee   string             `json:"assignee,omitempty"`
+	Resolution *DisputeResolution `json:"resolution,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ResolvedAt *time.Time         `json:"resolved_at,omitempty"`
+}
+
+func (d *Dispute) ValidateFields() error {
+	if d.Comment == "" {
+		return ErrEmptyDisputeComment
+	}
+
+	return nil
+}
+
+// ValidateDisputeStatus checks the status filter, empty one selects all disputes.
+func ValidateDisputeStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+
+	if _, ok := disputeStatuses[status]; !ok {
+		return ErrInvalidDisputeStatus
+	}
+
+	return nil
+}
+
+// DisputeResolution closes the dispute, REPOLL sends the invalid order to the
+// accrual system again and ADJUST applies the manual adjustment of Amount.
+type DisputeResolution struct {
+	Action       string           `json:"action"`
+	Comment      string           `json:"comment"`
+	Amount       *decimal.Decimal `json:"amount,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+	AdjustmentID *int64           `json:"adjustment_id,omitempty"`
+	Operator     string           `json:"operator"`
+}
+
+func (r *DisputeResolution) ValidateFields() error {
+	if _, ok := resolutionActions[r.Action]; !ok {
+		return ErrInvalidResolutionAction
+	}
+
+	if r.Comment == "" {
+		return ErrEmptyDisputeComment
+	}
+
+	if r.Action == ResolutionAdjust {
+		if r.Reason == "" {
+			r.Reason = ReasonCorrection
+		}
+
+		if r.Amount == nil {
+			return ErrInvalidAdjustmentAmount
+		}
+
+		adjustment := Adjustment{Amount: *r.Amount, Reason: r.Reason, Note: r.Comment, Operator: r.Operator}
+
+		return adjustment.ValidateFields()
+	}
+
+	return nil
+}
+
+// DisputeAssignment hands the dispute over to the admin, the operator
+// assigns it to themselves when Assignee is empty.
+type DisputeAssignment struct {
+	Assignee string `json:"assignee"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FraudFlag     = "FLAG"
+	FraudThrottle = "THROTTLE"
+	FraudReject   = "REJECT"
+)
+
+const (
+	RuleUploadRate   = "UPLOAD_RATE"
+	RuleInvalidRatio = "INVALID_RATIO"
+	RuleSharedIP     = "SHARED_IP"
+)
+
+var ErrInvalidFraudAction = errors.New("fraud action must be FLAG, THROTTLE or REJECT")
+
+// fraudSeverity orders the actions, the most severe of triggered rules wins.
+var fraudSeverity = map[string]int{
+	FraudFlag:     1,
+	FraudThrottle: 2,
+	FraudReject:   3,
+}
+
+// UploadAttempt is an order upload screened by the fraud rules, invalid
+// numbers are screened as well.
+type UploadAttempt struct {
+	Login  string
+	IP     string
+	Number string
+	Valid  bool
+}
+
+// UploadStats are the uploads within the fraud window including the screened one.
+type UploadStats struct {
+	Uploads  int
+	Invalid  int
+	Accounts int
+}
+
+// FraudRules are the velocity checks of order uploads within the window.
+// Zero MaxUploads, InvalidRatio or MaxAccountsPerIP disable the rule,
+// InvalidRatio applies after InvalidMinAttempts uploads.
+type FraudRules struct {
+	Window time.Duration
+
+	MaxUploads    int
+	UploadsAction string
+
+	InvalidRatio       decimal.Decimal
+	InvalidMinAttempts int
+	InvalidAction      string
+
+	MaxAccountsPerIP int
+	AccountsAction   string
+}
+
+// FraudDecision is the action of the most severe rule triggered by the upload.
+type FraudDecision struct {
+	Rule    string
+	Action  string
+	Details string
+}
+
+func (r *FraudRules) ValidateFields() error {
+	for _, action := range []string{r.UploadsAction, r.InvalidAction, r.AccountsAction} {
+		if _, ok := fraudSeverity[action]; !ok {
+			return ErrInvalidFraudAction
+		}
+	}
+
+	return nil
+}
+
+// IsSet reports whether any of the rules is enabled.
+func (r *FraudRules) IsSet() bool {
+	return r.MaxUploads > 0 || r.InvalidRatio.IsPositive() || r.MaxAccountsPerIP > 0
+}
+
+// Evaluate returns the decision on the upload, nil means the upload is allowed.
+func (r *FraudRules) Evaluate(stats UploadStats) *FraudDecision {
+	var decision *FraudDecision
+
+	trigger := func(rule string, action string, details string) {
+		if decision == nil || fraudSeverity[action] > fraudSeverity[decision.Action] {
+			decision = &FraudDecision{Rule: rule, Action: action, Details: details}
+		}
+	}
+
+	if r.MaxUploads > 0 && stats.Uploads > r.MaxUploads {
+		trigger(RuleUploadRate, r.UploadsAction,
+			fmt.Sprintf("%d uploads within %s, at most %d allowed", stats.Uploads, r.Window, r.MaxUploads))
+	}
+
+	if r.InvalidRatio.IsPositive() && stats.Uploads >= r.InvalidMinAttempts && stats.Uploads > 0 {
+		ratio := decimal.NewFromInt(int64(stats.Invalid)).Div(decimal.NewFromInt(int64(stats.Uploads)))
+		if ratio.GreaterThanOrEqual(r.InvalidRatio) {
+			trigger(RuleInvalidRatio, r.InvalidAction,
+				fmt.Sprintf("%d of %d uploads within %s are invalid", stats.Invalid, stats.Uploads, r.Window))
+		}
+	}
+
+	if r.MaxAccountsPerIP > 0 && stats.Accounts > r.MaxAccountsPerIP {
+		trigger(RuleSharedIP, r.AccountsAction,
+			fmt.Sprintf("%d accounts uploaded from the IP within %s, at most %d allowed",
+				stats.Accounts, r.Window, r.MaxAccountsPerIP))
+	}
+
+	return decision
+}
@@ -0,0 +1,84 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFraudRules(t *testing.T) {
+	rules := models.FraudRules{
+		Window:             time.Hour,
+		MaxUploads:         20,
+		UploadsAction:      models.FraudThrottle,
+		InvalidRatio:       decimal.RequireFromString("0.5"),
+		InvalidMinAttempts: 10,
+		InvalidAction:      models.FraudReject,
+		MaxAccountsPerIP:   3,
+		AccountsAction:     models.FraudFlag,
+	}
+	require.NoError(t, rules.ValidateFields())
+
+	tests := []struct {
+		name       string
+		stats      models.UploadStats
+		wantRule   string
+		wantAction string
+	}{
+		{name: "Allowed", stats: models.UploadStats{Uploads: 5, Invalid: 1, Accounts: 1}},
+		{name: "Few invalid attempts", stats: models.UploadStats{Uploads: 4, Invalid: 4, Accounts: 1}},
+		{
+			name:       "Too many uploads",
+			stats:      models.UploadStats{Uploads: 21, Invalid: 2, Accounts: 1},
+			wantRule:   models.RuleUploadRate,
+			wantAction: models.FraudThrottle,
+		},
+		{
+			name:       "Mostly invalid uploads",
+			stats:      models.UploadStats{Uploads: 10, Invalid: 5, Accounts: 1},
+			wantRule:   models.RuleInvalidRatio,
+			wantAction: models.FraudReject,
+		},
+		{
+			name:       "Shared IP",
+			stats:      models.UploadStats{Uploads: 1, Accounts: 4},
+			wantRule:   models.RuleSharedIP,
+			wantAction: models.FraudFlag,
+		},
+		{
+			name:       "Most severe action wins",
+			stats:      models.UploadStats{Uploads: 30, Invalid: 20, Accounts: 4},
+			wantRule:   models.RuleInvalidRatio,
+			wantAction: models.FraudReject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rules.Evaluate(tt.stats)
+			if tt.wantAction == "" {
+				assert.Nil(t, decision)
+
+				return
+			}
+
+			require.NotNil(t, decision)
+			assert.Equal(t, tt.wantRule, decision.Rule)
+			assert.Equal(t, tt.wantAction, decision.Action)
+		})
+	}
+}
+
+func TestFraudRulesInvalidAction(t *testing.T) {
+	rules := models.FraudRules{
+		UploadsAction:  models.FraudThrottle,
+		InvalidAction:  "BLOCK",
+		AccountsAction: models.FraudFlag,
+	}
+
+	assert.ErrorIs(t, rules.ValidateFields(), models.ErrInvalidFraudAction)
+}
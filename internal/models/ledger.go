@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	LedgerAccrual     = "ACCRUAL"
+	LedgerWithdrawal  = "WITHDRAWAL"
+	LedgerExpiration  = "EXPIRATION"
+	LedgerTransferIn  = "TRANSFER_IN"
+	LedgerTransferOut = "TRANSFER_OUT"
+	LedgerAdjustment  = "ADJUSTMENT"
+	LedgerTierBonus   = "TIER_BONUS"
+
+	LedgerCampaignBonus    = "CAMPAIGN_BONUS"
+	LedgerCampaignReversal = "CAMPAIGN_REVERSAL"
+	LedgerReferralBonus    = "REFERRAL_BONUS"
+	LedgerRedemption       = "REDEMPTION"
+	LedgerPromoCode        = "PROMO_CODE"
+	LedgerClaimWriteOff    = "CLAIM_WRITE_OFF"
+)
+
+type LedgerEntry struct {
+	Kind      string          `json:"kind"`
+	Amount    decimal.Decimal `json:"amount"`
+	Reference string          `json:"reference,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type Transfer struct {
+	Login string          `json:"login"`
+	Sum   decimal.Decimal `json:"sum"`
+}
+
+type StatementLine struct {
+	Kind      string          `json:"kind"`
+	Amount    decimal.Decimal `json:"amount"`
+	Reference string          `json:"reference,omitempty"`
+	Balance   decimal.Decimal `json:"balance"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Discrepancy is a difference between the balance computed from orders and
+// ledger and the balance materialized in accrual lots.
+type Discrepancy struct {
+	Login        string          `json:"login"`
+	Expected     decimal.Decimal `json:"expected"`
+	Materialized decimal.Decimal `json:"materialized"`
+	Difference   decimal.Decimal `json:"difference"`
+}
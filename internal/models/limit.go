@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	LimitDaily   = "DAILY"
+	LimitWeekly  = "WEEKLY"
+	LimitMonthly = "MONTHLY"
+)
+
+var (
+	ErrInvalidLimitPeriod = errors.New("limit period is invalid")
+	ErrInvalidLimitValue  = errors.New("limit must not be negative")
+)
+
+var limitWindows = map[string]time.Duration{
+	LimitDaily:   24 * time.Hour,
+	LimitWeekly:  7 * 24 * time.Hour,
+	LimitMonthly: 30 * 24 * time.Hour,
+}
+
+// WithdrawalLimit restricts withdrawals within the rolling window of the period,
+// zero MaxCount or MaxAmount means no limit.
+type WithdrawalLimit struct {
+	Period    string          `json:"period"`
+	MaxCount  int             `json:"max_count"`
+	MaxAmount decimal.Decimal `json:"max_amount"`
+}
+
+func (l *WithdrawalLimit) ValidateFields() error {
+	if _, ok := limitWindows[l.Period]; !ok {
+		return ErrInvalidLimitPeriod
+	}
+
+	if l.MaxCount < 0 || l.MaxAmount.IsNegative() {
+		return ErrInvalidLimitValue
+	}
+
+	return nil
+}
+
+func (l *WithdrawalLimit) Window() time.Duration {
+	return limitWindows[l.Period]
+}
+
+func (l *WithdrawalLimit) IsSet() bool {
+	return l.MaxCount > 0 || l.MaxAmount.IsPositive()
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMaturation = errors.New("maturation period definition is invalid")
+
+// MaturationPeriods is the cooling-off period after which processed accrual
+// becomes spendable. Partners may shorten or extend the default period, the
+// partner of the order is resolved from the key of the partner integration.
+type MaturationPeriods struct {
+	Default  time.Duration
+	Partners map[string]time.Duration
+}
+
+// UnmarshalText parses periods from the "duration;PARTNER=duration;..." form.
+func (m *MaturationPeriods) UnmarshalText(text []byte) error {
+	periods := MaturationPeriods{Partners: make(map[string]time.Duration)}
+
+	for _, definition := range strings.Split(string(text), ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		partner, value := "", definition
+		if i := strings.Index(definition, "="); i >= 0 {
+			partner, value = strings.TrimSpace(definition[:i]), strings.TrimSpace(definition[i+1:])
+			if partner == "" {
+				return fmt.Errorf("%w: %q", ErrInvalidMaturation, definition)
+			}
+		}
+
+		period, err := time.ParseDuration(value)
+		if err != nil || period < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidMaturation, definition)
+		}
+
+		if partner == "" {
+			periods.Default = period
+		} else {
+			periods.Partners[partner] = period
+		}
+	}
+
+	*m = periods
+
+	return nil
+}
+
+// For returns the period of the partner, unknown partners get the default one.
+func (m MaturationPeriods) For(partner string) time.Duration {
+	if period, ok := m.Partners[partner]; ok {
+		return period
+	}
+
+	return m.Default
+}
@@ -0,0 +1,27 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaturationPeriods(t *testing.T) {
+	var periods models.MaturationPeriods
+
+	err := periods.UnmarshalText([]byte("336h; SHOP=720h;CAFE=72h;TAXI=0s"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 336*time.Hour, periods.For(""))
+	assert.Equal(t, 720*time.Hour, periods.For("SHOP"))
+	assert.Equal(t, 72*time.Hour, periods.For("CAFE"))
+	assert.Equal(t, time.Duration(0), periods.For("TAXI"))
+	assert.Equal(t, 336*time.Hour, periods.For("BANK"))
+
+	for _, definition := range []string{"two weeks", "=72h", "SHOP=-1h"} {
+		assert.ErrorIs(t, periods.UnmarshalText([]byte(definition)), models.ErrInvalidMaturation, definition)
+	}
+}
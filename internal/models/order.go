@@ -4,30 +4,114 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"strconv"
 	"time"
 
-	"github.com/go-rfe/utils/luhn"
 	"github.com/shopspring/decimal"
 )
 
 const (
-	orderNumberBase    = 10
-	orderNumberBitSize = 64
+	OrderNew        = "NEW"
+	OrderProcessing = "PROCESSING"
+	OrderInvalid    = "INVALID"
+	OrderProcessed  = "PROCESSED"
+	OrderReview     = "REVIEW"
+	// OrderCancelled only closes the order history, cancelled orders are deleted.
+	OrderCancelled = "CANCELLED"
 )
 
-var ErrInvalidOrderNumber = errors.New("order number is invalid")
+const (
+	BatchAccepted     = "ACCEPTED"
+	BatchAlreadyYours = "ALREADY_YOURS"
+	BatchOwnedByOther = "OWNED_BY_OTHER"
+	BatchInvalid      = "INVALID"
+	BatchFailed       = "FAILED"
+	BatchRejected     = "REJECTED"
+)
+
+const (
+	HistorySourceUser    = "USER"
+	HistorySourceAccrual = "ACCRUAL"
+	HistorySourceDispute = "DISPUTE"
+	HistorySourceClaim   = "CLAIM"
+	HistorySourceReview  = "REVIEW"
+)
+
+const (
+	HoldAuthorized = "AUTHORIZED"
+	HoldCaptured   = "CAPTURED"
+	HoldVoided     = "VOIDED"
+	HoldExpired    = "EXPIRED"
+)
+
+var (
+	ErrInvalidOrderNumber = errors.New("order number is invalid")
+	ErrInvalidOrderStatus = errors.New("order status is invalid")
+)
+
+var orderStatuses = map[string]struct{}{
+	OrderNew:        {},
+	OrderProcessing: {},
+	OrderInvalid:    {},
+	OrderProcessed:  {},
+	OrderReview:     {},
+}
 
 type Order struct {
 	Number     string           `json:"number"`
+	Partner    string           `json:"partner,omitempty"`
 	Status     string           `json:"status"`
 	Accrual    *decimal.Decimal `json:"accrual,omitempty"`
 	UploadedAt time.Time        `json:"uploaded_at"`
 }
 
+// StatusChange is a transition of the order status, OldStatus is empty
+// for the upload of the order. Dispute steps keep the status and explain
+// themselves in Note.
+type StatusChange struct {
+	OldStatus string           `json:"old_status,omitempty"`
+	NewStatus string           `json:"new_status"`
+	Accrual   *decimal.Decimal `json:"accrual,omitempty"`
+	Source    string           `json:"source"`
+	Note      string           `json:"note,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// BatchResult is the outcome of one order number of the bulk upload.
+type BatchResult struct {
+	Number string `json:"number"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+type OrderDetails struct {
+	Order
+	History []StatusChange `json:"history"`
+}
+
+// OrdersFilter selects a page of orders with any of the statuses, orders are
+// sorted by upload time and number, newest first when Descending.
+type OrdersFilter struct {
+	Page       Page
+	Statuses   []string
+	Descending bool
+}
+
+func (f *OrdersFilter) ValidateFields() error {
+	for _, status := range f.Statuses {
+		if _, ok := orderStatuses[status]; !ok {
+			return ErrInvalidOrderStatus
+		}
+	}
+
+	return nil
+}
+
 type Balance struct {
 	Current   decimal.Decimal `json:"current"`
 	Withdrawn decimal.Decimal `json:"withdrawn"`
+	Pending   decimal.Decimal `json:"pending"`
+	Locked    decimal.Decimal `json:"locked"`
+	Expiring  decimal.Decimal `json:"expiring"`
 }
 
 type Withdraw struct {
@@ -36,17 +120,13 @@ type Withdraw struct {
 	ProcessedAt time.Time       `json:"processed_at"`
 }
 
-func Validate(number string) error {
-	numberInt, err := strconv.ParseInt(number, orderNumberBase, orderNumberBitSize)
-	if err != nil {
-		return err
-	}
-
-	if !luhn.Valid(numberInt) {
-		return ErrInvalidOrderNumber
-	}
-
-	return nil
+type Hold struct {
+	ID        int64           `json:"id"`
+	Order     string          `json:"order"`
+	Sum       decimal.Decimal `json:"sum"`
+	Status    string          `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
 }
 
 func Encode(data interface{}, w io.Writer) error {
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// Page selects items within the time range which follow the cursor.
+type Page struct {
+	From  time.Time
+	To    time.Time
+	After *Cursor
+	Limit int
+}
+
+// Cursor points to the last item of a page, items are ordered by time first
+// and by source and key for the items with equal time.
+type Cursor struct {
+	At     time.Time `json:"at"`
+	Source int       `json:"source,omitempty"`
+	Key    string    `json:"key"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Key == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidPartnerKeys = errors.New("partner keys definition is invalid")
+	ErrUnknownPartnerKey  = errors.New("partner key is unknown")
+)
+
+// PartnerKeys map the secret keys issued to partner integrations to the
+// partners, the partner of the upload is never taken from the client as is.
+type PartnerKeys map[string]string
+
+// UnmarshalText parses keys from the "key=PARTNER;..." form.
+func (k *PartnerKeys) UnmarshalText(text []byte) error {
+	keys := make(PartnerKeys)
+
+	for _, definition := range strings.Split(string(text), ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		i := strings.Index(definition, "=")
+		if i < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidPartnerKeys, definition)
+		}
+
+		key, partner := strings.TrimSpace(definition[:i]), strings.TrimSpace(definition[i+1:])
+		if key == "" || partner == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidPartnerKeys, definition)
+		}
+
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("%w: duplicate key of %s", ErrInvalidPartnerKeys, partner)
+		}
+
+		keys[key] = partner
+	}
+
+	*k = keys
+
+	return nil
+}
+
+// Partner returns the partner the key was issued to, uploads without a key
+// have no partner.
+func (k PartnerKeys) Partner(key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+
+	partner := ""
+	for known, p := range k {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(key)) == 1 {
+			partner = p
+		}
+	}
+
+	if partner == "" {
+		return "", ErrUnknownPartnerKey
+	}
+
+	return partner, nil
+}
+
+// ValidatePartners checks that each key selects the partner with a validator.
+func (k PartnerKeys) ValidatePartners(validators OrderValidators) error {
+	for _, partner := range k {
+		if _, ok := validators.Partners[partner]; !ok {
+			return fmt.Errorf("%w: %s has no validator", ErrUnknownPartner, partner)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartnerKeys(t *testing.T) {
+	var keys models.PartnerKeys
+
+	err := keys.UnmarshalText([]byte("bank-secret=BANK; shop-secret = SHOP"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		key         string
+		wantPartner string
+		wantErr     error
+	}{
+		{name: "No key"},
+		{name: "Bank key", key: "bank-secret", wantPartner: "BANK"},
+		{name: "Shop key", key: "shop-secret", wantPartner: "SHOP"},
+		{name: "Partner name as key", key: "BANK", wantErr: models.ErrUnknownPartnerKey},
+		{name: "Unknown key", key: "bank-secret2", wantErr: models.ErrUnknownPartnerKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partner, err := keys.Partner(tt.key)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantPartner, partner)
+		})
+	}
+}
+
+func TestPartnerKeysInvalid(t *testing.T) {
+	var keys models.PartnerKeys
+
+	for _, definition := range []string{"BANK", "=BANK", "secret=", "secret=BANK;secret=SHOP"} {
+		assert.ErrorIs(t, keys.UnmarshalText([]byte(definition)), models.ErrInvalidPartnerKeys, definition)
+	}
+}
+
+func TestPartnerKeysValidatePartners(t *testing.T) {
+	var (
+		keys       models.PartnerKeys
+		validators models.OrderValidators
+	)
+
+	require.NoError(t, validators.UnmarshalText([]byte("luhn;BANK=mod97")))
+
+	require.NoError(t, keys.UnmarshalText([]byte("bank-secret=BANK")))
+	assert.NoError(t, keys.ValidatePartners(validators))
+
+	require.NoError(t, keys.UnmarshalText([]byte("bank-secret=BANK;shop-secret=SHOP")))
+	assert.ErrorIs(t, keys.ValidatePartners(validators), models.ErrUnknownPartner)
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MaxPromoBatchSize = 10000
+
+	promoCodeSize = 10
+)
+
+var (
+	ErrEmptyPromoBatchName   = errors.New("batch name is required")
+	ErrInvalidPromoAmount    = errors.New("code amount must be positive")
+	ErrInvalidPromoBatchSize = errors.New("batch size is out of range")
+	ErrInvalidPromoMaxUses   = errors.New("code must be usable at least once")
+)
+
+// PromoBatch is a set of codes worth Amount points each, every code can be
+// redeemed MaxUses times by different users until ExpiresAt.
+type PromoBatch struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Amount    decimal.Decimal `json:"amount"`
+	Count     int             `json:"count"`
+	MaxUses   int             `json:"max_uses"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+	CreatedBy string          `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	Codes     []PromoCode     `json:"codes,omitempty"`
+}
+
+type PromoCode struct {
+	Code string `json:"code"`
+	Uses int    `json:"uses"`
+}
+
+type PromoRedemption struct {
+	Code   string          `json:"code"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+func (b *PromoBatch) ValidateFields() error {
+	if b.Name == "" {
+		return ErrEmptyPromoBatchName
+	}
+
+	if !b.Amount.IsPositive() {
+		return ErrInvalidPromoAmount
+	}
+
+	if b.Count < 1 || b.Count > MaxPromoBatchSize {
+		return ErrInvalidPromoBatchSize
+	}
+
+	if b.MaxUses < 1 {
+		return ErrInvalidPromoMaxUses
+	}
+
+	return nil
+}
+
+// NewPromoCode returns a random code hard enough to guess.
+func NewPromoCode() (string, error) {
+	return randomCode(promoCodeSize)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"time"
+)
+
+const (
+	ReferralPending  = "PENDING"
+	ReferralRewarded = "REWARDED"
+
+	codeSize = 5
+)
+
+type Referral struct {
+	Login      string     `json:"login"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RewardedAt *time.Time `json:"rewarded_at,omitempty"`
+}
+
+// Referrals are the user's own referral code and the users invited with it.
+type Referrals struct {
+	Code      string     `json:"code"`
+	Referrals []Referral `json:"referrals"`
+}
+
+// NewReferralCode returns a random code for inviting new users.
+func NewReferralCode() (string, error) {
+	return randomCode(codeSize)
+}
+
+func randomCode(size int) (string, error) {
+	randomBytes := make([]byte, size)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.EncodeToString(randomBytes), nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HeldAccrual is the order held in REVIEW instead of being processed.
+type HeldAccrual struct {
+	Number     string           `json:"number"`
+	Login      string           `json:"login"`
+	Partner    string           `json:"partner,omitempty"`
+	Accrual    *decimal.Decimal `json:"accrual,omitempty"`
+	Reason     string           `json:"reason"`
+	UploadedAt time.Time        `json:"uploaded_at"`
+}
+
+type ReviewDecision struct {
+	Approve bool `json:"approve"`
+}
+
+// ReviewPolicy holds the processed accrual for manual review when it exceeds
+// MaxAccrual or when the order was flagged on upload. Zero MaxAccrual
+// disables the threshold.
+type ReviewPolicy struct {
+	MaxAccrual decimal.Decimal
+	Flagged    bool
+}
+
+// Reason returns why the accrual is held, empty one means no review is needed.
+func (p *ReviewPolicy) Reason(accrual *decimal.Decimal, flagReason string) string {
+	if p.MaxAccrual.IsPositive() && accrual != nil && accrual.GreaterThan(p.MaxAccrual) {
+		return fmt.Sprintf("accrual %s exceeds %s", accrual, p.MaxAccrual)
+	}
+
+	if p.Flagged && flagReason != "" {
+		return fmt.Sprintf("flagged on upload: %s", flagReason)
+	}
+
+	return ""
+}
@@ -0,0 +1,39 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewPolicy(t *testing.T) {
+	policy := models.ReviewPolicy{MaxAccrual: decimal.NewFromInt(1000), Flagged: true}
+	small := decimal.NewFromInt(500)
+	limit := decimal.NewFromInt(1000)
+	large := decimal.NewFromInt(5000)
+
+	tests := []struct {
+		name       string
+		policy     models.ReviewPolicy
+		accrual    *decimal.Decimal
+		flagReason string
+		held       bool
+	}{
+		{name: "Small accrual", policy: policy, accrual: &small},
+		{name: "Accrual at threshold", policy: policy, accrual: &limit},
+		{name: "No accrual", policy: policy},
+		{name: "Large accrual", policy: policy, accrual: &large, held: true},
+		{name: "Flagged order", policy: policy, accrual: &small, flagReason: "SHARED_IP", held: true},
+		{name: "Flagged order ignored", policy: models.ReviewPolicy{}, accrual: &small, flagReason: "SHARED_IP"},
+		{name: "Threshold disabled", policy: models.ReviewPolicy{Flagged: true}, accrual: &large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.policy.Reason(tt.accrual, tt.flagReason)
+			assert.Equal(t, tt.held, reason != "", reason)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	RewardItem     = "ITEM"
+	RewardDiscount = "DISCOUNT"
+	RewardVoucher  = "VOUCHER"
+
+	redemptionCodeSize = 10
+)
+
+var (
+	ErrEmptyRewardName    = errors.New("reward name is required")
+	ErrInvalidRewardKind  = errors.New("reward kind is invalid")
+	ErrInvalidRewardPrice = errors.New("reward price must be positive")
+	ErrInvalidRewardStock = errors.New("reward stock must not be negative")
+)
+
+var rewardKinds = map[string]struct{}{
+	RewardItem:     {},
+	RewardDiscount: {},
+	RewardVoucher:  {},
+}
+
+// Reward is a catalog entry, nil Stock means the reward is unlimited.
+// Nil Active keeps the stored state, new rewards are active by default.
+type Reward struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Kind        string          `json:"kind"`
+	Description string          `json:"description,omitempty"`
+	Price       decimal.Decimal `json:"price"`
+	Stock       *int            `json:"stock,omitempty"`
+	Active      *bool           `json:"active"`
+}
+
+func (r *Reward) ValidateFields() error {
+	if r.Name == "" {
+		return ErrEmptyRewardName
+	}
+
+	if _, ok := rewardKinds[r.Kind]; !ok {
+		return ErrInvalidRewardKind
+	}
+
+	if !r.Price.IsPositive() {
+		return ErrInvalidRewardPrice
+	}
+
+	if r.Stock != nil && *r.Stock < 0 {
+		return ErrInvalidRewardStock
+	}
+
+	return nil
+}
+
+type Redemption struct {
+	ID        int64           `json:"id"`
+	RewardID  int64           `json:"reward_id"`
+	Reward    string          `json:"reward"`
+	Price     decimal.Decimal `json:"price"`
+	Code      string          `json:"code"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewRedemptionCode returns a random code the user presents to get the reward.
+func NewRedemptionCode() (string, error) {
+	return randomCode(redemptionCodeSize)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidTier = errors.New("tier definition is invalid")
+
+type Tier struct {
+	Name       string          `json:"name"`
+	Threshold  decimal.Decimal `json:"threshold"`
+	Multiplier decimal.Decimal `json:"multiplier"`
+}
+
+// Tiers are ordered by threshold, the first tier is the entry level.
+type Tiers []Tier
+
+// UnmarshalText parses tiers from the "NAME:threshold:multiplier,..." form.
+func (t *Tiers) UnmarshalText(text []byte) error {
+	tiers := make(Tiers, 0)
+
+	for _, definition := range strings.Split(string(text), ",") {
+		fields := strings.Split(strings.TrimSpace(definition), ":")
+		if len(fields) != 3 || fields[0] == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidTier, definition)
+		}
+
+		threshold, err := decimal.NewFromString(fields[1])
+		if err != nil || threshold.IsNegative() {
+			return fmt.Errorf("%w: %q", ErrInvalidTier, definition)
+		}
+
+		multiplier, err := decimal.NewFromString(fields[2])
+		if err != nil || !multiplier.IsPositive() {
+			return fmt.Errorf("%w: %q", ErrInvalidTier, definition)
+		}
+
+		tiers = append(tiers, Tier{Name: fields[0], Threshold: threshold, Multiplier: multiplier})
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].Threshold.LessThan(tiers[j].Threshold)
+	})
+
+	*t = tiers
+
+	return nil
+}
+
+// Reached returns the index of the highest tier reached with the accrued points.
+func (t Tiers) Reached(accrued decimal.Decimal) int {
+	reached := 0
+	for i, tier := range t {
+		if accrued.GreaterThanOrEqual(tier.Threshold) {
+			reached = i
+		}
+	}
+
+	return reached
+}
+
+// Index returns the index of the named tier, unknown tiers fall back to the entry level.
+func (t Tiers) Index(name string) int {
+	for i, tier := range t {
+		if tier.Name == name {
+			return i
+		}
+	}
+
+	return 0
+}
+
+type TierStatus struct {
+	Tier          string           `json:"tier"`
+	Multiplier    decimal.Decimal  `json:"multiplier"`
+	Accrued       decimal.Decimal  `json:"accrued"`
+	NextTier      string           `json:"next_tier,omitempty"`
+	NextThreshold *decimal.Decimal `json:"next_threshold,omitempty"`
+	Remaining     *decimal.Decimal `json:"remaining,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiers(t *testing.T) {
+	var tiers models.Tiers
+
+	err := tiers.UnmarshalText([]byte("GOLD:5000:1.5, BRONZE:0:1,SILVER:1000:1.25"))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		names = append(names, tier.Name)
+	}
+	assert.Equal(t, []string{"BRONZE", "SILVER", "GOLD"}, names)
+
+	tests := []struct {
+		name    string
+		accrued int64
+		want    string
+	}{
+		{name: "Entry level", accrued: 0, want: "BRONZE"},
+		{name: "Below threshold", accrued: 999, want: "BRONZE"},
+		{name: "Threshold reached", accrued: 1000, want: "SILVER"},
+		{name: "Top tier", accrued: 10000, want: "GOLD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tiers[tiers.Reached(decimal.NewFromInt(tt.accrued))].Name)
+		})
+	}
+
+	assert.Equal(t, 0, tiers.Index("PLATINUM"))
+	assert.ErrorIs(t, tiers.UnmarshalText([]byte("GOLD:5000")), models.ErrInvalidTier)
+	assert.ErrorIs(t, tiers.UnmarshalText([]byte("GOLD:5000:0")), models.ErrInvalidTier)
+}
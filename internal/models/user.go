@@ -12,6 +12,8 @@ var ErrInvalidPassword = errors.New("password is invalid")
 type User struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	// ReferralCode is the code of the inviting user given at registration.
+	ReferralCode string `json:"referral_code,omitempty"`
 }
 
 func (u *User) ValidateFields() error {
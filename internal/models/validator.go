@@ -0,0 +1,201 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	ValidatorLuhn  = "luhn"
+	ValidatorMod97 = "mod97"
+	ValidatorEAN   = "ean"
+	ValidatorRegex = "regex"
+)
+
+var (
+	ErrInvalidValidator = errors.New("order number validator definition is invalid")
+	ErrUnknownPartner   = errors.New("partner is unknown")
+)
+
+// NumberValidator checks the order number of the partner.
+type NumberValidator func(number string) error
+
+// OrderValidators select the number validator by partner, the default one
+// validates orders uploaded without a partner.
+type OrderValidators struct {
+	Default  NumberValidator
+	Partners map[string]NumberValidator
+}
+
+// UnmarshalText parses validators from the "scheme;PARTNER=scheme;..." form,
+// the scheme is one of luhn, mod97, ean or regex:<pattern>.
+func (v *OrderValidators) UnmarshalText(text []byte) error {
+	validators := OrderValidators{Partners: make(map[string]NumberValidator)}
+
+	for _, definition := range strings.Split(string(text), ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+
+		partner, scheme := "", definition
+		if i := strings.Index(definition, "="); i >= 0 && !strings.HasPrefix(definition, ValidatorRegex+":") {
+			partner, scheme = strings.TrimSpace(definition[:i]), strings.TrimSpace(definition[i+1:])
+			if partner == "" {
+				return fmt.Errorf("%w: %q", ErrInvalidValidator, definition)
+			}
+		}
+
+		validator, err := NewNumberValidator(scheme)
+		if err != nil {
+			return fmt.Errorf("%w: %q", err, definition)
+		}
+
+		if partner == "" {
+			validators.Default = validator
+		} else {
+			validators.Partners[partner] = validator
+		}
+	}
+
+	*v = validators
+
+	return nil
+}
+
+// Validate checks the number with the validator of the partner,
+// Luhn is used when no default validator is configured.
+func (v OrderValidators) Validate(partner string, number string) error {
+	if partner == "" {
+		if v.Default == nil {
+			return Validate(number)
+		}
+
+		return v.Default(number)
+	}
+
+	validator, ok := v.Partners[partner]
+	if !ok {
+		return ErrUnknownPartner
+	}
+
+	return validator(number)
+}
+
+// NewNumberValidator returns the validator of the scheme.
+func NewNumberValidator(scheme string) (NumberValidator, error) {
+	switch {
+	case scheme == ValidatorLuhn:
+		return Validate, nil
+	case scheme == ValidatorMod97:
+		return validateMod97, nil
+	case scheme == ValidatorEAN:
+		return validateEAN, nil
+	case strings.HasPrefix(scheme, ValidatorRegex+":"):
+		pattern, err := regexp.Compile(strings.TrimPrefix(scheme, ValidatorRegex+":"))
+		if err != nil {
+			return nil, ErrInvalidValidator
+		}
+
+		return func(number string) error {
+			if !pattern.MatchString(number) {
+				return ErrInvalidOrderNumber
+			}
+
+			return nil
+		}, nil
+	}
+
+	return nil, ErrInvalidValidator
+}
+
+// Validate checks the Luhn checksum of the number of any length.
+func Validate(number string) error {
+	if !isDigits(number) {
+		return ErrInvalidOrderNumber
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	if sum%10 != 0 {
+		return ErrInvalidOrderNumber
+	}
+
+	return nil
+}
+
+// validateMod97 checks the ISO 7064 MOD 97-10 check digits of the number.
+func validateMod97(number string) error {
+	if len(number) < 3 || !isDigits(number) {
+		return ErrInvalidOrderNumber
+	}
+
+	remainder := 0
+	for i := 0; i < len(number); i++ {
+		remainder = (remainder*10 + int(number[i]-'0')) % 97
+	}
+
+	if remainder != 1 {
+		return ErrInvalidOrderNumber
+	}
+
+	return nil
+}
+
+// validateEAN checks the check digit of EAN-8, UPC-A, EAN-13 and GTIN-14 numbers.
+func validateEAN(number string) error {
+	switch len(number) {
+	case 8, 12, 13, 14:
+	default:
+		return ErrInvalidOrderNumber
+	}
+
+	if !isDigits(number) {
+		return ErrInvalidOrderNumber
+	}
+
+	sum := 0
+	for i := len(number) - 2; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if (len(number)-2-i)%2 == 0 {
+			digit *= 3
+		}
+
+		sum += digit
+	}
+
+	if (10-sum%10)%10 != int(number[len(number)-1]-'0') {
+		return ErrInvalidOrderNumber
+	}
+
+	return nil
+}
+
+func isDigits(number string) bool {
+	if number == "" {
+		return false
+	}
+
+	for i := 0; i < len(number); i++ {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
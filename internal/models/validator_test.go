@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderValidators(t *testing.T) {
+	var validators models.OrderValidators
+
+	err := validators.UnmarshalText([]byte("luhn; BANK=mod97;SHOP=ean;TAXI=regex:^TX-[0-9]{6}$"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		partner string
+		number  string
+		wantErr error
+	}{
+		{name: "Luhn", number: "267876232367723"},
+		{name: "Luhn longer than int64", number: "12345678901234567890123459"},
+		{name: "Bad Luhn", number: "12345678901234567890123450", wantErr: models.ErrInvalidOrderNumber},
+		{name: "Not digits", number: "2678762323677x3", wantErr: models.ErrInvalidOrderNumber},
+		{name: "MOD 97-10", partner: "BANK", number: "123456789012345678901235"},
+		{name: "Bad MOD 97-10", partner: "BANK", number: "123456789012345678901234", wantErr: models.ErrInvalidOrderNumber},
+		{name: "EAN-13", partner: "SHOP", number: "4006381333931"},
+		{name: "UPC-A", partner: "SHOP", number: "036000291452"},
+		{name: "Bad EAN-13", partner: "SHOP", number: "4006381333932", wantErr: models.ErrInvalidOrderNumber},
+		{name: "Regex", partner: "TAXI", number: "TX-123456"},
+		{name: "Regex mismatch", partner: "TAXI", number: "TX-12345", wantErr: models.ErrInvalidOrderNumber},
+		{name: "Unknown partner", partner: "CAFE", number: "267876232367723", wantErr: models.ErrUnknownPartner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, validators.Validate(tt.partner, tt.number), tt.wantErr)
+		})
+	}
+}
+
+func TestOrderValidatorsInvalid(t *testing.T) {
+	var validators models.OrderValidators
+
+	for _, definition := range []string{"crc32", "=luhn", "SHOP=regex:[0-9"} {
+		assert.ErrorIs(t, validators.UnmarshalText([]byte(definition)), models.ErrInvalidValidator, definition)
+	}
+}
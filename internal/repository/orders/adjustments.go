@@ -0,0 +1,107 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// Adjust applies the signed manual adjustment to the user's balance.
+func (db *DBStore) Adjust(ctx context.Context, adjustment *models.Adjustment) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	if err := db.adjust(ctx, tx, adjustment); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DBStore) adjust(ctx context.Context, tx *sql.Tx, adjustment *models.Adjustment) error {
+	if err := userExists(ctx, tx, adjustment.Login); err != nil {
+		return err
+	}
+
+	row := tx.QueryRowContext(ctx,
+		"INSERT INTO adjustments (login, amount, reason, note, operator) VALUES ($1, $2, $3, $4, $5) "+
+			"RETURNING id, created_at",
+		adjustment.Login, adjustment.Amount, adjustment.Reason, adjustment.Note, adjustment.Operator)
+
+	err := row.Scan(&adjustment.ID, &adjustment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	reference := strconv.FormatInt(adjustment.ID, 10)
+	if adjustment.Amount.IsPositive() {
+		err = db.credit(ctx, tx, adjustment.Login, reference, adjustment.Amount)
+	} else {
+		err = db.debit(ctx, tx, adjustment.Login, adjustment.Amount.Neg())
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO ledger (login, kind, amount, reference, created_at) VALUES ($1, $2, $3, $4, $5)",
+		adjustment.Login, models.LedgerAdjustment, adjustment.Amount, reference, adjustment.CreatedAt)
+
+	return err
+}
+
+func (db *DBStore) GetAdjustments(ctx context.Context, login string) ([]models.Adjustment, error) {
+	adjustments := make([]models.Adjustment, 0)
+
+	adjustmentsRows, err := db.connection.QueryContext(ctx,
+		"SELECT id,login,amount,reason,note,operator,created_at FROM adjustments WHERE login = $1 ORDER BY id",
+		login)
+
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(adjustmentsRows)
+
+	for adjustmentsRows.Next() {
+		var a models.Adjustment
+		err = adjustmentsRows.Scan(&a.ID, &a.Login, &a.Amount, &a.Reason, &a.Note, &a.Operator, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		adjustments = append(adjustments, a)
+	}
+
+	err = adjustmentsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+func userExists(ctx context.Context, tx *sql.Tx, login string) error {
+	var existing string
+
+	row := tx.QueryRowContext(ctx, "SELECT login FROM users WHERE login = $1", login)
+	err := row.Scan(&existing)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+
+	return err
+}
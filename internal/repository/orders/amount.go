@@ -0,0 +1,49 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// debitKinds are the ledger entries spent by the user, they count towards
+// the daily maximum and the withdrawal limits together with withdrawals and
+// active holds.
+var debitKinds = []string{models.LedgerTransferOut, models.LedgerRedemption}
+
+// checkDailyAmount validates the amount against the user's debits of today.
+// The caller must hold the user's lots locked.
+func (db *DBStore) checkDailyAmount(ctx context.Context, tx *sql.Tx, login string, amount decimal.Decimal) error {
+	if !db.cfg.Amount.MaxDaily.IsPositive() {
+		return nil
+	}
+
+	var spent decimal.Decimal
+	row := tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COALESCE(SUM(withdraw), 0) FROM orders
+				WHERE login = $1 AND withdraw IS NOT NULL AND uploaded_at >= date_trunc('day', now()))
+			+ (SELECT COALESCE(SUM(sum), 0) FROM withdrawal_holds
+				WHERE login = $1 AND status = $2 AND expires_at > now() AND created_at >= date_trunc('day', now()))
+			+ (SELECT COALESCE(-SUM(amount), 0) FROM ledger
+				WHERE login = $1 AND kind = ANY($3) AND created_at >= date_trunc('day', now()))`,
+		login, models.HoldAuthorized, debitKinds)
+
+	if err := row.Scan(&spent); err != nil {
+		return err
+	}
+
+	return db.cfg.Amount.ValidateDaily(spent, amount)
+}
+
+// truncatePoints drops the fractions of points finer than the amount policy allows,
+// zero decimal places keep whole points only.
+func (db *DBStore) truncatePoints(amount decimal.Decimal) decimal.Decimal {
+	if places := db.cfg.Amount.MaxDecimalPlaces; places >= 0 {
+		return amount.Truncate(places)
+	}
+
+	return amount
+}
@@ -0,0 +1,29 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncatePoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		places int32
+		want   string
+	}{
+		{name: "Two places", places: 2, want: "12.34"},
+		{name: "Whole points", places: 0, want: "12"},
+		{name: "Any precision", places: -1, want: "12.3456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := NewDBStore(nil, Config{Amount: models.AmountPolicy{MaxDecimalPlaces: tt.places}})
+			got := db.truncatePoints(decimal.RequireFromString("12.3456"))
+			assert.True(t, decimal.RequireFromString(tt.want).Equal(got), got.String())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func recordAudit(ctx context.Context, e execer, event *models.AuditEvent) error {
+	_, err := e.ExecContext(ctx,
+		"INSERT INTO audit_events (login, actor, action, details) VALUES ($1, $2, $3, $4)",
+		event.Login, event.Actor, event.Action, event.Details)
+
+	return err
+}
+
+func (db *DBStore) GetAuditEvents(ctx context.Context, login string) ([]models.AuditEvent, error) {
+	events := make([]models.AuditEvent, 0)
+
+	eventsRows, err := db.connection.QueryContext(ctx,
+		"SELECT id,login,COALESCE(actor, ''),action,COALESCE(details, ''),created_at FROM audit_events "+
+			"WHERE login = $1 ORDER BY id", login)
+
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(eventsRows)
+
+	for eventsRows.Next() {
+		var event models.AuditEvent
+		err = eventsRows.Scan(&event.ID, &event.Login, &event.Actor, &event.Action, &event.Details, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	err = eventsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
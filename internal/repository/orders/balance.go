@@ -0,0 +1,91 @@
+package orders
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// GetBalance reports processed accrual which hasn't matured yet as pending,
+// it becomes current once the maturation period passes. Orders still being
+// processed count as pending with the accrual the accrual system reported so
+// far, orders without one are estimated by the user's average accrual.
+func (db *DBStore) GetBalance(ctx context.Context, login string) (*models.Balance, error) {
+	var (
+		balance     models.Balance
+		accrued     decimal.Decimal
+		adjusted    decimal.Decimal
+		maturing    decimal.Decimal
+		unestimated int64
+		estimate    decimal.Decimal
+	)
+
+	row := db.connection.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(accrual) FILTER (WHERE status = 'PROCESSED' AND withdraw IS NULL), 0),
+			COALESCE(SUM(accrual) FILTER (WHERE status IN ('NEW', 'PROCESSING', 'REVIEW') AND withdraw IS NULL), 0),
+			COALESCE(SUM(withdraw), 0),
+			(SELECT COALESCE(SUM(amount), 0) FROM ledger WHERE login = $1),
+			(SELECT COALESCE(SUM(sum), 0) FROM withdrawal_holds
+				WHERE login = $1 AND status = $3 AND expires_at > now()),
+			(SELECT COALESCE(SUM(remaining), 0) FROM accrual_lots
+				WHERE login = $1 AND remaining > 0 AND expires_at <= now() + $2 * interval '1 second'),
+			(SELECT COALESCE(SUM(remaining), 0) FROM accrual_lots
+				WHERE login = $1 AND remaining > 0 AND available_at > now()),
+			COUNT(*) FILTER (WHERE status IN ('NEW', 'PROCESSING') AND accrual IS NULL AND withdraw IS NULL),
+			COALESCE(AVG(accrual) FILTER (WHERE status = 'PROCESSED' AND accrual > 0 AND withdraw IS NULL), 0)
+		FROM orders WHERE login = $1`,
+		login, intervalSeconds(db.cfg.ExpiryWarning), models.HoldAuthorized)
+
+	err := row.Scan(&accrued, &balance.Pending, &balance.Withdrawn, &adjusted, &balance.Locked, &balance.Expiring,
+		&maturing, &unestimated, &estimate)
+	if err != nil {
+		return nil, err
+	}
+
+	estimated := db.truncatePoints(estimate.Mul(decimal.NewFromInt(unestimated)))
+	balance.Pending = balance.Pending.Add(maturing).Add(estimated)
+	balance.Current = accrued.Sub(balance.Withdrawn).Add(adjusted).Sub(balance.Locked).Sub(maturing)
+
+	return &balance, nil
+}
+
+// GetBalanceAsOf rebuilds the balance at the given moment from timestamped
+// accruals, withdrawals, holds and ledger entries. Accruals count from the time
+// the order became PROCESSED and stay pending until they mature, points which
+// were expiring at that moment are not reported.
+func (db *DBStore) GetBalanceAsOf(ctx context.Context, login string, asOf time.Time) (*models.Balance, error) {
+	var (
+		balance  models.Balance
+		accrued  decimal.Decimal
+		adjusted decimal.Decimal
+		maturing decimal.Decimal
+	)
+
+	row := db.connection.QueryRowContext(ctx, `
+		SELECT
+			COALESCE(SUM(accrual) FILTER (WHERE status = 'PROCESSED' AND withdraw IS NULL
+				AND processed_at <= $2), 0),
+			COALESCE(SUM(accrual) FILTER (WHERE status = 'PROCESSED' AND withdraw IS NULL
+				AND uploaded_at <= $2 AND processed_at > $2), 0),
+			COALESCE(SUM(withdraw) FILTER (WHERE uploaded_at <= $2), 0),
+			(SELECT COALESCE(SUM(amount), 0) FROM ledger WHERE login = $1 AND created_at <= $2),
+			(SELECT COALESCE(SUM(sum), 0) FROM withdrawal_holds
+				WHERE login = $1 AND created_at <= $2 AND expires_at > $2 AND (closed_at IS NULL OR closed_at > $2)),
+			(SELECT COALESCE(SUM(amount), 0) FROM accrual_lots
+				WHERE login = $1 AND accrued_at <= $2 AND available_at > $2)
+		FROM orders WHERE login = $1`,
+		login, asOf.UTC())
+
+	err := row.Scan(&accrued, &balance.Pending, &balance.Withdrawn, &adjusted, &balance.Locked, &maturing)
+	if err != nil {
+		return nil, err
+	}
+
+	balance.Pending = balance.Pending.Add(maturing)
+	balance.Current = accrued.Sub(balance.Withdrawn).Add(adjusted).Sub(balance.Locked).Sub(maturing)
+
+	return &balance, nil
+}
@@ -0,0 +1,59 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBalancePending(t *testing.T) {
+	tests := []struct {
+		name        string
+		pending     string
+		maturing    string
+		unestimated int64
+		estimate    string
+		wantPending int64
+		wantCurrent int64
+	}{
+		{name: "Nothing pending", pending: "0", maturing: "0", estimate: "0", wantCurrent: 700},
+		{name: "Reported accrual", pending: "120", maturing: "0", estimate: "350", wantPending: 120, wantCurrent: 700},
+		{
+			name: "Estimated accrual", pending: "120", maturing: "0", unestimated: 2, estimate: "350.5",
+			wantPending: 821, wantCurrent: 700,
+		},
+		{
+			name: "Maturing accrual", pending: "0", maturing: "200", unestimated: 1, estimate: "350",
+			wantPending: 550, wantCurrent: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{results: []fakeResult{
+				{
+					match: "FROM orders WHERE login",
+					columns: []string{"accrued", "pending", "withdrawn", "adjusted", "locked", "expiring", "maturing",
+						"unestimated", "estimate"},
+					rows: [][]driver.Value{
+						{"1000", tt.pending, "300", "0", "0", "0", tt.maturing, tt.unestimated, tt.estimate},
+					},
+				},
+			}}
+
+			store := NewDBStore(sql.OpenDB(fake), Config{})
+			defer store.Close()
+
+			balance, err := store.GetBalance(context.Background(), "test")
+			require.NoError(t, err)
+
+			assert.True(t, decimal.NewFromInt(tt.wantPending).Equal(balance.Pending), balance.Pending.String())
+			assert.True(t, decimal.NewFromInt(tt.wantCurrent).Equal(balance.Current), balance.Current.String())
+		})
+	}
+}
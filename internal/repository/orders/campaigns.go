@@ -0,0 +1,277 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrCampaignNotFound = errors.New("campaign not found")
+
+type campaignBonus struct {
+	id     int64
+	login  string
+	number string
+	amount decimal.Decimal
+}
+
+func (db *DBStore) CreateCampaign(ctx context.Context, campaign *models.Campaign) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	row := tx.QueryRowContext(ctx,
+		"INSERT INTO campaigns (name, starts_at, ends_at, multiplier, bonus) VALUES ($1, $2, $3, $4, $5) "+
+			"RETURNING id",
+		campaign.Name, campaign.StartsAt.UTC(), campaign.EndsAt.UTC(), campaign.Multiplier, campaign.Bonus)
+
+	err = row.Scan(&campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, login := range campaign.Logins {
+		if err := userExists(ctx, tx, login); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO campaign_users (campaign_id, login) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			campaign.ID, login)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *DBStore) GetCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	return queryCampaigns(ctx, db.connection, "TRUE")
+}
+
+// activeCampaigns returns the campaigns running now which target the user.
+func activeCampaigns(ctx context.Context, q querier, login string) ([]models.Campaign, error) {
+	return queryCampaigns(ctx, q,
+		"c.starts_at <= now() AND c.ends_at > now() AND "+
+			"(NOT EXISTS (SELECT 1 FROM campaign_users t WHERE t.campaign_id = c.id) OR "+
+			"EXISTS (SELECT 1 FROM campaign_users t WHERE t.campaign_id = c.id AND t.login = $1))",
+		login)
+}
+
+func queryCampaigns(ctx context.Context, q querier, condition string, args ...interface{}) ([]models.Campaign, error) {
+	campaigns := make([]models.Campaign, 0)
+
+	campaignsRows, err := q.QueryContext(ctx,
+		"SELECT c.id, c.name, c.starts_at, c.ends_at, c.multiplier, c.bonus, "+
+			"COALESCE((SELECT string_agg(login, ',' ORDER BY login) FROM campaign_users WHERE campaign_id = c.id), '') "+
+			"FROM campaigns c WHERE "+condition+" ORDER BY c.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(campaignsRows)
+
+	for campaignsRows.Next() {
+		var (
+			c      models.Campaign
+			logins string
+		)
+
+		err = campaignsRows.Scan(&c.ID, &c.Name, &c.StartsAt, &c.EndsAt, &c.Multiplier, &c.Bonus, &logins)
+		if err != nil {
+			return nil, err
+		}
+
+		if logins != "" {
+			c.Logins = strings.Split(logins, ",")
+		}
+
+		campaigns = append(campaigns, c)
+	}
+
+	err = campaignsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// applyCampaigns credits the bonus of every active campaign as its own ledger line.
+func (db *DBStore) applyCampaigns(ctx context.Context, tx *sql.Tx, login string, number string,
+	accrual decimal.Decimal, maturation time.Duration) error {
+	campaigns, err := activeCampaigns(ctx, tx, login)
+	if err != nil {
+		return err
+	}
+
+	for i := range campaigns {
+		bonus := db.truncatePoints(campaigns[i].BonusFor(accrual))
+		if !bonus.IsPositive() {
+			continue
+		}
+
+		if err := db.creditMaturing(ctx, tx, login, number, bonus, maturation); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+			login, models.LedgerCampaignBonus, bonus, number)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO campaign_bonuses (campaign_id, login, number, amount) VALUES ($1, $2, $3, $4)",
+			campaigns[i].ID, login, number, bonus)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DBStore) GetCampaignReport(ctx context.Context, campaignID int64) (*models.CampaignReport, error) {
+	if err := campaignExists(ctx, db.connection, campaignID); err != nil {
+		return nil, err
+	}
+
+	report := models.CampaignReport{CampaignID: campaignID}
+
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT COUNT(*), COUNT(DISTINCT login), COALESCE(SUM(amount), 0), COALESCE(SUM(reversed_amount), 0) "+
+			"FROM campaign_bonuses WHERE campaign_id = $1", campaignID)
+
+	err := row.Scan(&report.Bonuses, &report.Users, &report.Total, &report.Reversed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ReverseCampaign stops the campaign and takes its bonuses back. Points which
+// were already spent can't be taken back, so a bonus may be reversed partially.
+// Bonuses which haven't matured yet are taken back as well.
+func (db *DBStore) ReverseCampaign(ctx context.Context, campaignID int64) (*models.CampaignReport, error) {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx)
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE campaigns SET ends_at = LEAST(ends_at, now()) WHERE id = $1", campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ErrCampaignNotFound
+	}
+
+	bonuses, err := lockCampaignBonuses(ctx, tx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bonus := range bonuses {
+		lots, available, err := lockOwnedLots(ctx, tx, bonus.login)
+		if err != nil {
+			return nil, err
+		}
+
+		reversed := decimal.Max(decimal.Min(bonus.amount, available), decimal.Zero)
+		if err := consumeLots(ctx, tx, lots, reversed); err != nil {
+			return nil, err
+		}
+
+		if reversed.IsPositive() {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+				bonus.login, models.LedgerCampaignReversal, reversed.Neg(), bonus.number)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE campaign_bonuses SET reversed_amount = $1, reversed_at = now() WHERE id = $2",
+			reversed, bonus.id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return db.GetCampaignReport(ctx, campaignID)
+}
+
+func lockCampaignBonuses(ctx context.Context, tx *sql.Tx, campaignID int64) ([]campaignBonus, error) {
+	bonuses := make([]campaignBonus, 0)
+
+	bonusesRows, err := tx.QueryContext(ctx,
+		"SELECT id, login, number, amount FROM campaign_bonuses "+
+			"WHERE campaign_id = $1 AND reversed_at IS NULL ORDER BY login, id FOR UPDATE", campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(bonusesRows)
+
+	for bonusesRows.Next() {
+		var b campaignBonus
+		err = bonusesRows.Scan(&b.id, &b.login, &b.number, &b.amount)
+		if err != nil {
+			return nil, err
+		}
+
+		bonuses = append(bonuses, b)
+	}
+
+	err = bonusesRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return bonuses, nil
+}
+
+func campaignExists(ctx context.Context, q queryRower, campaignID int64) error {
+	var id int64
+
+	row := q.QueryRowContext(ctx, "SELECT id FROM campaigns WHERE id = $1", campaignID)
+
+	err := row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCampaignNotFound
+	}
+
+	return err
+}
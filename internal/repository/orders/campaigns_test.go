@@ -0,0 +1,48 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseCampaignTakesMaturingBonus(t *testing.T) {
+	fake := &fakeDB{results: []fakeResult{
+		{
+			match:   "reversed_at IS NULL",
+			columns: []string{"id", "login", "number", "amount"},
+			rows:    [][]driver.Value{{int64(1), "user", "12345678903", "50"}},
+		},
+		// the bonus lot is still maturing, so it isn't spendable yet
+		{match: "available_at <= now()"},
+		{
+			match:   "FROM accrual_lots",
+			columns: []string{"id", "remaining"},
+			rows:    [][]driver.Value{{int64(7), "50"}},
+		},
+		{match: "FROM withdrawal_holds", columns: []string{"sum"}, rows: [][]driver.Value{{"0"}}},
+		{match: "SELECT id FROM campaigns", columns: []string{"id"}, rows: [][]driver.Value{{int64(3)}}},
+		{
+			match:   "COUNT(*)",
+			columns: []string{"count", "users", "total", "reversed"},
+			rows:    [][]driver.Value{{int64(1), int64(1), "50", "50"}},
+		},
+	}}
+
+	store := NewDBStore(sql.OpenDB(fake), Config{})
+	defer store.Close()
+
+	report, err := store.ReverseCampaign(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.CampaignID)
+
+	assert.Equal(t, [][]driver.Value{{"50", int64(7)}}, fake.execArgs("UPDATE accrual_lots"))
+	assert.Equal(t, [][]driver.Value{{"user", models.LedgerCampaignReversal, "-50", "12345678903"}},
+		fake.execArgs("INSERT INTO ledger"))
+	assert.Equal(t, [][]driver.Value{{"50", int64(1)}}, fake.execArgs("UPDATE campaign_bonuses"))
+}
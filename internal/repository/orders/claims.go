@@ -0,0 +1,395 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/jackc/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrClaimNotFound = errors.New("claim not found")
+	ErrClaimExists   = errors.New("order already has an open claim")
+	ErrClaimOwnOrder = errors.New("order already belongs to the user")
+	ErrClaimClosed   = errors.New("claim is not open anymore")
+	ErrClaimDisputed = errors.New("order has an open dispute")
+)
+
+const claimColumns = "id, number, claimant, owner, COALESCE(proof, ''), status, COALESCE(contest_comment, ''), " +
+	"COALESCE(decider, ''), deadline, created_at, decided_at FROM order_claims"
+
+// claimSystemDecider marks claims decided by the deadline job.
+const claimSystemDecider = "system"
+
+// CreateClaim files the claim of the user on the order uploaded by someone
+// else, the owner may contest it until the deadline.
+func (db *DBStore) CreateClaim(ctx context.Context, claim *models.Claim) error {
+	var pgErr *pgconn.PgError
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var status string
+	row := tx.QueryRowContext(ctx,
+		"SELECT login, status FROM orders WHERE number = $1 AND withdraw IS NULL FOR UPDATE", claim.Number)
+
+	err = row.Scan(&claim.Owner, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if claim.Owner == claim.Claimant {
+		return ErrClaimOwnOrder
+	}
+
+	if err := checkOpenDispute(ctx, tx, claim.Number); err != nil {
+		return err
+	}
+
+	row = tx.QueryRowContext(ctx,
+		"INSERT INTO order_claims (number, claimant, owner, proof, status, deadline) "+
+			"VALUES ($1, $2, $3, NULLIF($4, ''), $5, now() + $6 * interval '1 second') "+
+			"RETURNING id, status, deadline, created_at",
+		claim.Number, claim.Claimant, claim.Owner, claim.Proof, models.ClaimPending, db.cfg.ClaimWindow.Seconds())
+
+	err = row.Scan(&claim.ID, &claim.Status, &claim.Deadline, &claim.CreatedAt)
+	if errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation {
+		return ErrClaimExists
+	}
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(ctx, tx, &models.AuditEvent{
+		Login:   claim.Owner,
+		Actor:   claim.Claimant,
+		Action:  models.AuditOrderClaimed,
+		Details: fmt.Sprintf("claim %d on order %s", claim.ID, claim.Number),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = recordStatusChange(ctx, tx, claim.Number, &models.StatusChange{
+		OldStatus: status,
+		NewStatus: status,
+		Source:    models.HistorySourceClaim,
+		Note:      fmt.Sprintf("claim %d filed", claim.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The claimant doesn't learn who uploaded the order.
+	claim.Owner = ""
+
+	return tx.Commit()
+}
+
+// GetClaims returns the claims filed by the user and the claims on the
+// user's orders, the other party is not disclosed.
+func (db *DBStore) GetClaims(ctx context.Context, login string) ([]models.Claim, error) {
+	claims, err := queryClaimRows(ctx, db.connection,
+		"SELECT "+claimColumns+" WHERE claimant = $1 OR owner = $1 ORDER BY id", login)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range claims {
+		if claims[i].Claimant == login {
+			claims[i].Owner = ""
+		} else {
+			claims[i].Claimant = ""
+		}
+	}
+
+	return claims, nil
+}
+
+// ListClaims returns the claims of all users in the status, empty status
+// selects all of them.
+func (db *DBStore) ListClaims(ctx context.Context, status string) ([]models.Claim, error) {
+	return queryClaimRows(ctx, db.connection,
+		"SELECT "+claimColumns+" WHERE ($1 = '' OR status = $1) ORDER BY id", status)
+}
+
+// ContestClaim stops the automatic transfer of the order, an admin decides
+// on the contested claim.
+func (db *DBStore) ContestClaim(ctx context.Context, login string, id int64, comment string) error {
+	result, err := db.connection.ExecContext(ctx,
+		"UPDATE order_claims SET status = $1, contest_comment = $2 "+
+			"WHERE id = $3 AND owner = $4 AND status = $5 AND deadline > now()",
+		models.ClaimContested, comment, id, login, models.ClaimPending)
+	if err != nil {
+		return err
+	}
+
+	contested, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if contested > 0 {
+		return nil
+	}
+
+	var existing int64
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT id FROM order_claims WHERE id = $1 AND owner = $2", id, login)
+
+	err = row.Scan(&existing)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrClaimNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return ErrClaimClosed
+}
+
+// DecideClaim approves or rejects the open claim, the approved claim moves
+// the order to the claimant.
+func (db *DBStore) DecideClaim(ctx context.Context, id int64, approve bool, decider string) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var claim models.Claim
+	row := tx.QueryRowContext(ctx,
+		"SELECT id, number, claimant, owner, status FROM order_claims WHERE id = $1 FOR UPDATE", id)
+
+	err = row.Scan(&claim.ID, &claim.Number, &claim.Claimant, &claim.Owner, &claim.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrClaimNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if claim.Status != models.ClaimPending && claim.Status != models.ClaimContested {
+		return ErrClaimClosed
+	}
+
+	if approve {
+		err = db.moveClaimedOrder(ctx, tx, &claim, decider)
+	} else {
+		err = closeClaim(ctx, tx, claim.ID, models.ClaimRejected, decider)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferClaimedOrders moves the orders of the claims which weren't
+// contested before the deadline. It returns the number of moved orders.
+func (db *DBStore) TransferClaimedOrders(ctx context.Context) (int64, error) {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(tx)
+
+	claims, err := queryClaimRows(ctx, tx,
+		"SELECT "+claimColumns+" WHERE status = $1 AND deadline <= now() ORDER BY id FOR UPDATE SKIP LOCKED",
+		models.ClaimPending)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	for i := range claims {
+		err = db.moveClaimedOrder(ctx, tx, &claims[i], claimSystemDecider)
+		if errors.Is(err, ErrOrderNotFound) {
+			// The owner cancelled the order, there is nothing to move.
+			err = closeClaim(ctx, tx, claims[i].ID, models.ClaimRejected, claimSystemDecider)
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+		if errors.Is(err, ErrClaimDisputed) {
+			// The claim waits until the owner's dispute is resolved.
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		moved++
+	}
+
+	return moved, tx.Commit()
+}
+
+// moveClaimedOrder hands the order over to the claimant together with its
+// accrual. Points the owner has already spent are written off. The order
+// with an open dispute stays with the owner who filed it.
+func (db *DBStore) moveClaimedOrder(ctx context.Context, tx *sql.Tx, claim *models.Claim, decider string) error {
+	var (
+		status  string
+		partner string
+		accrual *decimal.Decimal
+	)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT status, COALESCE(partner, ''), accrual FROM orders "+
+			"WHERE number = $1 AND login = $2 AND withdraw IS NULL FOR UPDATE",
+		claim.Number, claim.Owner)
+
+	err := row.Scan(&status, &partner, &accrual)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := checkOpenDispute(ctx, tx, claim.Number); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE orders SET login = $1 WHERE number = $2", claim.Claimant, claim.Number)
+	if err != nil {
+		return err
+	}
+
+	if status == models.OrderProcessed && accrual != nil && accrual.IsPositive() {
+		if err := db.moveAccrual(ctx, tx, claim, *accrual, db.cfg.Maturation.For(partner)); err != nil {
+			return err
+		}
+	}
+
+	err = recordStatusChange(ctx, tx, claim.Number, &models.StatusChange{
+		OldStatus: status,
+		NewStatus: status,
+		Source:    models.HistorySourceClaim,
+		Note:      fmt.Sprintf("order moved by claim %d", claim.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, login := range []string{claim.Owner, claim.Claimant} {
+		err = recordAudit(ctx, tx, &models.AuditEvent{
+			Login:  login,
+			Actor:  decider,
+			Action: models.AuditOrderClaimTransferred,
+			Details: fmt.Sprintf("order %s moved from %s to %s by claim %d",
+				claim.Number, claim.Owner, claim.Claimant, claim.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return closeClaim(ctx, tx, claim.ID, models.ClaimApproved, decider)
+}
+
+// moveAccrual takes the order accrual from the owner's lots, maturing ones
+// included, and credits it to the claimant to mature again. The part the
+// owner can't cover is written off.
+func (db *DBStore) moveAccrual(ctx context.Context, tx *sql.Tx, claim *models.Claim, accrual decimal.Decimal,
+	maturation time.Duration) error {
+	lots, _, err := lockOwnedLots(ctx, tx, claim.Owner)
+	if err != nil {
+		return err
+	}
+
+	remaining := decimal.Zero
+	for _, l := range lots {
+		remaining = remaining.Add(l.remaining)
+	}
+
+	taken := decimal.Min(remaining, accrual)
+	if err := consumeLots(ctx, tx, lots, taken); err != nil {
+		return err
+	}
+
+	if shortfall := accrual.Sub(taken); shortfall.IsPositive() {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+			claim.Owner, models.LedgerClaimWriteOff, shortfall, claim.Number)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.creditMaturing(ctx, tx, claim.Claimant, claim.Number, accrual, maturation)
+}
+
+// checkOpenDispute refuses to move the order while its dispute is unresolved,
+// the dispute is bound to the login which filed it.
+func checkOpenDispute(ctx context.Context, tx *sql.Tx, number string) error {
+	var disputes int
+	row := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM disputes WHERE number = $1 AND status <> $2", number, models.DisputeResolved)
+	if err := row.Scan(&disputes); err != nil {
+		return err
+	}
+
+	if disputes > 0 {
+		return ErrClaimDisputed
+	}
+
+	return nil
+}
+
+func closeClaim(ctx context.Context, tx *sql.Tx, id int64, status string, decider string) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE order_claims SET status = $1, decider = $2, decided_at = now() WHERE id = $3",
+		status, decider, id)
+
+	return err
+}
+
+func queryClaimRows(ctx context.Context, q querier, query string, args ...interface{}) ([]models.Claim, error) {
+	claims := make([]models.Claim, 0)
+
+	claimsRows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(claimsRows)
+
+	for claimsRows.Next() {
+		var c models.Claim
+		err = claimsRows.Scan(&c.ID, &c.Number, &c.Claimant, &c.Owner, &c.Proof, &c.Status, &c.ContestComment,
+			&c.Decider, &c.Deadline, &c.CreatedAt, &c.DecidedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		claims = append(claims, c)
+	}
+
+	err = claimsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
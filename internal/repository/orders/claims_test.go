@@ -0,0 +1,97 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimsWithOpenDispute(t *testing.T) {
+	tests := []struct {
+		name      string
+		disputes  int64
+		wantErr   error
+		wantMoved bool
+	}{
+		{name: "Approve claim", wantMoved: true},
+		{name: "Approve claim on disputed order", disputes: 1, wantErr: ErrClaimDisputed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{results: []fakeResult{
+				{
+					match:   "FROM order_claims WHERE id",
+					columns: []string{"id", "number", "claimant", "owner", "status"},
+					rows:    [][]driver.Value{{int64(5), "267876232367723", "claimant", "owner", models.ClaimContested}},
+				},
+				{
+					match:   "SELECT status, COALESCE(partner, ''), accrual FROM orders",
+					columns: []string{"status", "partner", "accrual"},
+					rows:    [][]driver.Value{{models.OrderNew, "", nil}},
+				},
+				{match: "FROM disputes", columns: []string{"count"}, rows: [][]driver.Value{{tt.disputes}}},
+			}}
+
+			store := NewDBStore(sql.OpenDB(fake), Config{})
+			defer store.Close()
+
+			err := store.DecideClaim(context.Background(), 5, true, "admin")
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.wantMoved, len(fake.execArgs("UPDATE orders SET login")) > 0)
+			assert.Equal(t, tt.wantMoved, len(fake.execArgs("UPDATE order_claims")) > 0)
+		})
+	}
+}
+
+func TestCreateClaimOnDisputedOrder(t *testing.T) {
+	fake := &fakeDB{results: []fakeResult{
+		{match: "SELECT login, status FROM orders", columns: []string{"login", "status"},
+			rows: [][]driver.Value{{"owner", models.OrderProcessed}}},
+		{match: "FROM disputes", columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}},
+	}}
+
+	store := NewDBStore(sql.OpenDB(fake), Config{})
+	defer store.Close()
+
+	err := store.CreateClaim(context.Background(), &models.Claim{Number: "267876232367723", Claimant: "claimant"})
+	assert.ErrorIs(t, err, ErrClaimDisputed)
+	assert.Empty(t, fake.execArgs("INSERT INTO audit_events"))
+}
+
+func TestTransferClaimedOrdersSkipsDisputed(t *testing.T) {
+	deadline := time.Now().Add(-time.Hour)
+
+	fake := &fakeDB{results: []fakeResult{
+		{
+			match: "FROM order_claims WHERE status",
+			columns: []string{"id", "number", "claimant", "owner", "proof", "status", "contest_comment",
+				"decider", "deadline", "created_at", "decided_at"},
+			rows: [][]driver.Value{
+				{int64(5), "267876232367723", "claimant", "owner", "", models.ClaimPending, "", "", deadline, deadline, nil},
+			},
+		},
+		{
+			match:   "SELECT status, COALESCE(partner, ''), accrual FROM orders",
+			columns: []string{"status", "partner", "accrual"},
+			rows:    [][]driver.Value{{models.OrderNew, "", nil}},
+		},
+		{match: "FROM disputes", columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}},
+	}}
+
+	store := NewDBStore(sql.OpenDB(fake), Config{})
+	defer store.Close()
+
+	moved, err := store.TransferClaimedOrders(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, moved)
+
+	assert.Empty(t, fake.execArgs("UPDATE orders SET login"))
+	assert.Empty(t, fake.execArgs("UPDATE order_claims"))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/jackc/pgconn"
 	_ "github.com/jackc/pgx/v4/stdlib" // init postgresql driver
+	"github.com/shopspring/decimal"
 )
 
 const (
@@ -17,22 +18,30 @@ const (
 
 type DBStore struct {
 	connection *sql.DB
+	cfg        Config
 }
 
-func NewDBStore(connection *sql.DB) *DBStore {
+func NewDBStore(connection *sql.DB, cfg Config) *DBStore {
 	db := DBStore{
 		connection: connection,
+		cfg:        cfg,
 	}
 
 	return &db
 }
 
-func (db *DBStore) CreateOrder(ctx context.Context, login string, order string) error {
+func (db *DBStore) CreateOrder(ctx context.Context, login string, order string, partner string) error {
 	var pgErr *pgconn.PgError
 
-	_, err := db.connection.ExecContext(ctx,
-		"INSERT INTO orders (number, login) VALUES ($1, $2)",
-		order, login)
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO orders (number, login, partner) VALUES ($1, $2, NULLIF($3, ''))",
+		order, login, partner)
 
 	switch {
 	case err != nil && errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation:
@@ -55,24 +64,118 @@ func (db *DBStore) CreateOrder(ctx context.Context, login string, order string)
 		return err
 	}
 
-	return nil
+	err = recordStatusChange(ctx, tx, order, &models.StatusChange{
+		NewStatus: models.OrderNew,
+		Source:    models.HistorySourceUser,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (db *DBStore) UpdateOrder(ctx context.Context, order *models.Order) error {
-	_, err := db.connection.ExecContext(ctx, "UPDATE orders set accrual = $1, status = $2 WHERE number = $3",
-		order.Accrual, order.Status, order.Number)
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var login, status, partner, flagReason string
+	row := tx.QueryRowContext(ctx,
+		"SELECT login, status, COALESCE(partner, ''), COALESCE(flag_reason, '') FROM orders "+
+			"WHERE number = $1 FOR UPDATE", order.Number)
+	if err := row.Scan(&login, &status, &partner, &flagReason); err != nil {
+		return err
+	}
+
+	newStatus, reviewReason := order.Status, ""
+	if newStatus == models.OrderProcessed && status != models.OrderProcessed {
+		reviewReason = db.cfg.Review.Reason(order.Accrual, flagReason)
+		if reviewReason != "" {
+			newStatus = models.OrderReview
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE orders set accrual = $1, status = $2, "+
+			"processed_at = CASE WHEN $2 = 'PROCESSED' THEN COALESCE(processed_at, now()) END, "+
+			"review_reason = NULLIF($3, '') WHERE number = $4",
+		order.Accrual, newStatus, reviewReason, order.Number)
+	if err != nil {
+		return err
+	}
+
+	if newStatus != status {
+		err = recordStatusChange(ctx, tx, order.Number, &models.StatusChange{
+			OldStatus: status,
+			NewStatus: newStatus,
+			Accrual:   order.Accrual,
+			Source:    models.HistorySourceAccrual,
+			Note:      reviewReason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if newStatus == models.OrderProcessed && status != models.OrderProcessed {
+		if err := db.completeOrder(ctx, tx, login, order.Number, partner, order.Accrual); err != nil {
+			return err
+		}
+	}
 
-	return err
+	return tx.Commit()
 }
 
-func (db *DBStore) GetOrders(ctx context.Context, login string) ([]models.Order, error) {
-	orders := make([]models.Order, 0)
+// completeOrder credits the accrual of the order which became PROCESSED,
+// the points mature within the period of the order partner.
+func (db *DBStore) completeOrder(ctx context.Context, tx *sql.Tx, login string, number string, partner string,
+	accrual *decimal.Decimal) error {
+	maturation := db.cfg.Maturation.For(partner)
+
+	if accrual != nil {
+		if err := db.accrue(ctx, tx, login, number, *accrual, maturation); err != nil {
+			return err
+		}
+	}
+
+	return db.payReferral(ctx, tx, login, maturation)
+}
+
+// GetOrders returns the page of user orders matching the filter. Next page
+// cursor is nil for the last page.
+func (db *DBStore) GetOrders(ctx context.Context, login string,
+	filter models.OrdersFilter) ([]models.Order, *models.Cursor, error) {
+	var afterAt, afterKey, statuses interface{}
+	if filter.Page.After != nil {
+		afterAt, afterKey = filter.Page.After.At.UTC(), filter.Page.After.Key
+	}
+	if len(filter.Statuses) > 0 {
+		statuses = filter.Statuses
+	}
+
+	comparison, direction := ">", "ASC"
+	if filter.Descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	orders := make([]models.Order, 0, filter.Page.Limit)
 
 	ordersRows, err := db.connection.QueryContext(ctx,
-		"SELECT number,accrual,status,uploaded_at FROM orders WHERE login = $1 AND withdraw IS NULL", login)
+		"SELECT number,COALESCE(partner, ''),accrual,status,uploaded_at FROM orders "+
+			"WHERE login = $1 AND withdraw IS NULL "+
+			"AND ($2::text[] IS NULL OR status = ANY($2::text[])) "+
+			"AND ($3::timestamp IS NULL OR uploaded_at >= $3::timestamp) "+
+			"AND ($4::timestamp IS NULL OR uploaded_at < $4::timestamp) "+
+			"AND ($5::timestamp IS NULL OR (uploaded_at, number) "+comparison+" ($5::timestamp, $6::text)) "+
+			"ORDER BY uploaded_at "+direction+", number "+direction+" LIMIT $7",
+		login, statuses, nullTime(filter.Page.From), nullTime(filter.Page.To), afterAt, afterKey,
+		filter.Page.Limit+1)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -83,9 +186,9 @@ func (db *DBStore) GetOrders(ctx context.Context, login string) ([]models.Order,
 
 	for ordersRows.Next() {
 		var order models.Order
-		err = ordersRows.Scan(&order.Number, &order.Accrual, &order.Status, &order.UploadedAt)
+		err = ordersRows.Scan(&order.Number, &order.Partner, &order.Accrual, &order.Status, &order.UploadedAt)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		orders = append(orders, order)
@@ -93,10 +196,16 @@ func (db *DBStore) GetOrders(ctx context.Context, login string) ([]models.Order,
 
 	err = ordersRows.Err()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return orders, nil
+	if len(orders) <= filter.Page.Limit {
+		return orders, nil, nil
+	}
+
+	last := orders[filter.Page.Limit-1]
+
+	return orders[:filter.Page.Limit], &models.Cursor{At: last.UploadedAt, Key: last.Number}, nil
 }
 
 func (db *DBStore) GetProcessedOrders(ctx context.Context, login string) ([]models.Order, error) {
@@ -137,7 +246,7 @@ func (db *DBStore) GetUnprocessedOrders(ctx context.Context) ([]models.Order, er
 	orders := make([]models.Order, 0)
 
 	ordersRows, err := db.connection.QueryContext(ctx,
-		"SELECT number FROM orders WHERE status = 'NEW'")
+		"SELECT number FROM orders WHERE status IN ('NEW', 'PROCESSING') AND withdraw IS NULL")
 
 	if err != nil {
 		return nil, err
@@ -168,12 +277,21 @@ func (db *DBStore) GetUnprocessedOrders(ctx context.Context) ([]models.Order, er
 }
 
 func (db *DBStore) Withdraw(ctx context.Context, login string, withdraw *models.Withdraw) error {
-	var existingOrder int64
+	if err := db.cfg.Amount.Validate(withdraw.Sum); err != nil {
+		return err
+	}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
 	var orderLogin string
-	row := db.connection.QueryRowContext(ctx,
-		"SELECT number, login FROM orders WHERE number = $1", withdraw.Order)
+	row := tx.QueryRowContext(ctx,
+		"SELECT login FROM orders WHERE number = $1", withdraw.Order)
 
-	err := row.Scan(&existingOrder, &orderLogin)
+	err = row.Scan(&orderLogin)
 	if !errors.Is(err, nil) && !errors.Is(err, sql.ErrNoRows) {
 		return err
 	}
@@ -185,10 +303,25 @@ func (db *DBStore) Withdraw(ctx context.Context, login string, withdraw *models.
 		return ErrOrderExists
 	}
 
-	_, err = db.connection.ExecContext(ctx,
+	if err := db.debit(ctx, tx, login, withdraw.Sum); err != nil {
+		return err
+	}
+
+	if err := db.checkDailyAmount(ctx, tx, login, withdraw.Sum); err != nil {
+		return err
+	}
+
+	if err := db.checkWithdrawalLimits(ctx, tx, login, withdraw.Sum); err != nil {
+		return db.auditLimitBreach(ctx, login, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
 		"INSERT INTO orders (number, login, withdraw) VALUES ($1, $2, $3)", withdraw.Order, login, withdraw.Sum)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
 
 func (db *DBStore) GetWithdrawals(ctx context.Context, login string) ([]models.Withdraw, error) {
@@ -228,3 +361,10 @@ func (db *DBStore) GetWithdrawals(ctx context.Context, login string) ([]models.W
 func (db *DBStore) Close() error {
 	return db.connection.Close()
 }
+
+func rollback(tx *sql.Tx) {
+	err := tx.Rollback()
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Error().Err(err).Msgf("Couldn't rollback transaction")
+	}
+}
@@ -0,0 +1,57 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateOrderMaturesWithPartnerPeriod(t *testing.T) {
+	maturation := models.MaturationPeriods{
+		Default:  336 * time.Hour,
+		Partners: map[string]time.Duration{"CAFE": 72 * time.Hour, "SHOP": 720 * time.Hour},
+	}
+
+	tests := []struct {
+		partner string
+		want    interface{}
+	}{
+		{partner: "", want: (336 * time.Hour).Seconds()},
+		{partner: "CAFE", want: (72 * time.Hour).Seconds()},
+		{partner: "SHOP", want: (720 * time.Hour).Seconds()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.partner, func(t *testing.T) {
+			fake := &fakeDB{results: []fakeResult{
+				{
+					match:   "SELECT login, status, COALESCE(partner, '')",
+					columns: []string{"login", "status", "partner", "flag_reason"},
+					rows:    [][]driver.Value{{"test", models.OrderProcessing, tt.partner, ""}},
+				},
+			}}
+
+			store := NewDBStore(sql.OpenDB(fake), Config{Maturation: maturation})
+			defer store.Close()
+
+			accrual := decimal.NewFromInt(500)
+			err := store.UpdateOrder(context.Background(), &models.Order{
+				Number:  "267876232367723",
+				Status:  models.OrderProcessed,
+				Accrual: &accrual,
+			})
+			require.NoError(t, err)
+
+			lots := fake.execArgs("INSERT INTO accrual_lots")
+			require.Len(t, lots, 1)
+			assert.Equal(t, tt.want, lots[0][4])
+		})
+	}
+}
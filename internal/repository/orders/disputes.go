@@ -0,0 +1,278 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/jackc/pgconn"
+)
+
+var (
+	ErrDisputeNotFound      = errors.New("dispute not found")
+	ErrDisputeExists        = errors.New("order already has an unresolved dispute")
+	ErrDisputeResolved      = errors.New("dispute is already resolved")
+	ErrDisputeNotRepollable = errors.New("only INVALID orders can be polled again")
+)
+
+const disputeColumns = "d.id, d.number, d.login, d.comment, d.status, COALESCE(d.assignee, ''), d.action, " +
+	"COALESCE(d.resolution_comment, ''), d.adjustment_id, a.amount, COALESCE(a.reason, ''), " +
+	"COALESCE(d.operator, ''), d.created_at, d.resolved_at " +
+	"FROM disputes d LEFT JOIN adjustments a ON a.id = d.adjustment_id"
+
+// OpenDispute files the user complaint about one of their orders.
+func (db *DBStore) OpenDispute(ctx context.Context, dispute *models.Dispute) error {
+	var pgErr *pgconn.PgError
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	status, err := lockUserOrder(ctx, tx, dispute.Login, dispute.Number)
+	if err != nil {
+		return err
+	}
+
+	row := tx.QueryRowContext(ctx,
+		"INSERT INTO disputes (number, login, comment, status) VALUES ($1, $2, $3, $4) "+
+			"RETURNING id, status, created_at",
+		dispute.Number, dispute.Login, dispute.Comment, models.DisputeOpen)
+
+	err = row.Scan(&dispute.ID, &dispute.Status, &dispute.CreatedAt)
+	if errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation {
+		return ErrDisputeExists
+	}
+	if err != nil {
+		return err
+	}
+
+	err = recordDisputeStep(ctx, tx, dispute.Number, status,
+		fmt.Sprintf("dispute %d opened: %s", dispute.ID, dispute.Comment))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDisputes returns the disputes of the user.
+func (db *DBStore) GetDisputes(ctx context.Context, login string) ([]models.Dispute, error) {
+	return db.queryDisputes(ctx,
+		"SELECT "+disputeColumns+" WHERE d.login = $1 ORDER BY d.id", login)
+}
+
+// ListDisputes returns the disputes of all users in the status, empty
+// status selects all of them.
+func (db *DBStore) ListDisputes(ctx context.Context, status string) ([]models.Dispute, error) {
+	return db.queryDisputes(ctx,
+		"SELECT "+disputeColumns+" WHERE ($1 = '' OR d.status = $1) ORDER BY d.id", status)
+}
+
+// AssignDispute hands the unresolved dispute over to the assignee.
+func (db *DBStore) AssignDispute(ctx context.Context, id int64, assignee string, actor string) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	number, login, err := lockDispute(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	status, err := lockUserOrder(ctx, tx, login, number)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE disputes SET assignee = $1, status = $2 WHERE id = $3",
+		assignee, models.DisputeAssigned, id)
+	if err != nil {
+		return err
+	}
+
+	err = recordDisputeStep(ctx, tx, number, status,
+		fmt.Sprintf("dispute %d assigned to %s by %s", id, assignee, actor))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResolveDispute closes the dispute, the order is polled again or the user
+// balance is adjusted according to the resolution action.
+func (db *DBStore) ResolveDispute(ctx context.Context, id int64, resolution *models.DisputeResolution) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	number, login, err := lockDispute(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	status, err := lockUserOrder(ctx, tx, login, number)
+	if err != nil {
+		return err
+	}
+
+	switch resolution.Action {
+	case models.ResolutionRepoll:
+		if status != models.OrderInvalid {
+			return ErrDisputeNotRepollable
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE orders SET status = $1, accrual = NULL, processed_at = NULL WHERE number = $2",
+			models.OrderNew, number)
+		if err != nil {
+			return err
+		}
+
+		err = recordStatusChange(ctx, tx, number, &models.StatusChange{
+			OldStatus: status,
+			NewStatus: models.OrderNew,
+			Source:    models.HistorySourceDispute,
+			Note:      fmt.Sprintf("dispute %d: order polled again", id),
+		})
+		if err != nil {
+			return err
+		}
+
+		status = models.OrderNew
+	case models.ResolutionAdjust:
+		adjustment := models.Adjustment{
+			Login:    login,
+			Amount:   *resolution.Amount,
+			Reason:   resolution.Reason,
+			Note:     fmt.Sprintf("dispute %d on order %s: %s", id, number, resolution.Comment),
+			Operator: resolution.Operator,
+		}
+
+		if err := db.adjust(ctx, tx, &adjustment); err != nil {
+			return err
+		}
+
+		resolution.AdjustmentID = &adjustment.ID
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE disputes SET status = $1, action = $2, resolution_comment = $3, adjustment_id = $4, "+
+			"operator = $5, resolved_at = now() WHERE id = $6",
+		models.DisputeResolved, resolution.Action, resolution.Comment, resolution.AdjustmentID,
+		resolution.Operator, id)
+	if err != nil {
+		return err
+	}
+
+	err = recordDisputeStep(ctx, tx, number, status,
+		fmt.Sprintf("dispute %d resolved with %s by %s: %s", id, resolution.Action, resolution.Operator,
+			resolution.Comment))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockDispute locks the unresolved dispute and returns its order and user.
+func lockDispute(ctx context.Context, tx *sql.Tx, id int64) (string, string, error) {
+	var number, login, status string
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT number, login, status FROM disputes WHERE id = $1 FOR UPDATE", id)
+
+	err := row.Scan(&number, &login, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", ErrDisputeNotFound
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if status == models.DisputeResolved {
+		return "", "", ErrDisputeResolved
+	}
+
+	return number, login, nil
+}
+
+// lockUserOrder locks the order of the user and returns its status.
+func lockUserOrder(ctx context.Context, tx *sql.Tx, login string, number string) (string, error) {
+	var status string
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT status FROM orders WHERE number = $1 AND login = $2 AND withdraw IS NULL FOR UPDATE",
+		number, login)
+
+	err := row.Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrOrderNotFound
+	}
+
+	return status, err
+}
+
+// recordDisputeStep adds the dispute step to the order history keeping its status.
+func recordDisputeStep(ctx context.Context, tx *sql.Tx, number string, status string, note string) error {
+	return recordStatusChange(ctx, tx, number, &models.StatusChange{
+		OldStatus: status,
+		NewStatus: status,
+		Source:    models.HistorySourceDispute,
+		Note:      note,
+	})
+}
+
+func (db *DBStore) queryDisputes(ctx context.Context, query string, args ...interface{}) ([]models.Dispute, error) {
+	disputes := make([]models.Dispute, 0)
+
+	disputesRows, err := db.connection.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(disputesRows)
+
+	for disputesRows.Next() {
+		var (
+			d          models.Dispute
+			action     sql.NullString
+			resolution models.DisputeResolution
+		)
+
+		err = disputesRows.Scan(&d.ID, &d.Number, &d.Login, &d.Comment, &d.Status, &d.Assignee, &action,
+			&resolution.Comment, &resolution.AdjustmentID, &resolution.Amount, &resolution.Reason,
+			&resolution.Operator, &d.CreatedAt, &d.ResolvedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if action.Valid {
+			resolution.Action = action.String
+			d.Resolution = &resolution
+		}
+
+		disputes = append(disputes, d)
+	}
+
+	err = disputesRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
@@ -0,0 +1,117 @@
+package orders
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+)
+
+// fakeResult answers the queries containing match with the rows.
+type fakeResult struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB is a database/sql driver answering queries with the first matching
+// result and recording executed statements. Unmatched queries return no rows.
+type fakeDB struct {
+	mu      sync.Mutex
+	results []fakeResult
+	execs   []fakeExec
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+// execArgs returns the arguments of the statements containing match.
+func (f *fakeDB) execArgs(match string) [][]driver.Value {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var args [][]driver.Value
+	for _, e := range f.execs {
+		if strings.Contains(e.query, match) {
+			args = append(args, e.args)
+		}
+	}
+
+	return args
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *fakeConn) Commit() error                       { return nil }
+func (c *fakeConn) Rollback() error                     { return nil }
+
+// CheckNamedValue converts arguments like database/sql does, slices are
+// passed through as pgx encodes them as arrays.
+func (c *fakeConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.([]string); ok {
+		return nil
+	}
+
+	var err error
+	nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+
+	return err
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return c, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.db.execs = append(c.db.execs, fakeExec{query: query, args: values})
+
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	for _, result := range c.db.results {
+		if strings.Contains(query, result.match) {
+			return &fakeRows{columns: result.columns, rows: result.rows}, nil
+		}
+	}
+
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+// ScreenUpload records the upload attempt and evaluates the fraud rules
+// against the uploads within the window. Triggered rules are audited,
+// nil decision means the upload is allowed.
+func (db *DBStore) ScreenUpload(ctx context.Context, attempt *models.UploadAttempt) (*models.FraudDecision, error) {
+	rules := db.cfg.Fraud
+	if !rules.IsSet() {
+		return nil, nil
+	}
+
+	_, err := db.connection.ExecContext(ctx,
+		"INSERT INTO upload_attempts (login, ip, number, valid) VALUES ($1, $2, $3, $4)",
+		attempt.Login, attempt.IP, attempt.Number, attempt.Valid)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats models.UploadStats
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT COUNT(*) FILTER (WHERE login = $1), COUNT(*) FILTER (WHERE login = $1 AND NOT valid), "+
+			"COUNT(DISTINCT login) FILTER (WHERE ip = $2) FROM upload_attempts "+
+			"WHERE (login = $1 OR ip = $2) AND created_at > now() - $3 * interval '1 second'",
+		attempt.Login, attempt.IP, rules.Window.Seconds())
+
+	if err := row.Scan(&stats.Uploads, &stats.Invalid, &stats.Accounts); err != nil {
+		return nil, err
+	}
+
+	decision := rules.Evaluate(stats)
+	if decision == nil {
+		return nil, nil
+	}
+
+	log.Info().Msgf("Fraud rule %s decided %s on order %s upload by %s from %s: %s",
+		decision.Rule, decision.Action, attempt.Number, attempt.Login, attempt.IP, decision.Details)
+
+	err = recordAudit(ctx, db.connection, &models.AuditEvent{
+		Login:  attempt.Login,
+		Action: models.AuditFraudDecision,
+		Details: fmt.Sprintf("%s %s order %s from %s: %s",
+			decision.Rule, decision.Action, attempt.Number, attempt.IP, decision.Details),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decision, nil
+}
+
+// FlagOrder marks the uploaded order as suspicious.
+func (db *DBStore) FlagOrder(ctx context.Context, number string, reason string) error {
+	_, err := db.connection.ExecContext(ctx,
+		"UPDATE orders SET flag_reason = $1 WHERE number = $2", reason, number)
+
+	return err
+}
+
+// PurgeUploadAttempts deletes the attempts which are out of the fraud window
+// and returns the number of deleted ones.
+func (db *DBStore) PurgeUploadAttempts(ctx context.Context) (int64, error) {
+	result, err := db.connection.ExecContext(ctx,
+		"DELETE FROM upload_attempts WHERE created_at <= now() - $1 * interval '1 second'",
+		db.cfg.Fraud.Window.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,141 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrOrderNotCancellable = errors.New("order can be cancelled only while NEW or INVALID")
+	ErrOrderHasOpenCase    = errors.New("order has an open dispute or claim")
+)
+
+func recordStatusChange(ctx context.Context, tx *sql.Tx, number string, change *models.StatusChange) error {
+	var oldStatus interface{}
+	if change.OldStatus != "" {
+		oldStatus = change.OldStatus
+	}
+
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO order_status_history (number, old_status, new_status, accrual, source, note) "+
+			"VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))",
+		number, oldStatus, change.NewStatus, change.Accrual, change.Source, change.Note)
+
+	return err
+}
+
+// GetOrder returns the user order with the timeline of its status changes.
+func (db *DBStore) GetOrder(ctx context.Context, login string, number string) (*models.OrderDetails, error) {
+	details := models.OrderDetails{History: make([]models.StatusChange, 0)}
+
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT number,COALESCE(partner, ''),accrual,status,uploaded_at FROM orders "+
+			"WHERE number = $1 AND login = $2 AND withdraw IS NULL",
+		number, login)
+
+	err := row.Scan(&details.Number, &details.Partner, &details.Accrual, &details.Status, &details.UploadedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	historyRows, err := db.connection.QueryContext(ctx,
+		"SELECT COALESCE(old_status, ''),new_status,accrual,source,COALESCE(note, ''),created_at "+
+			"FROM order_status_history "+
+			"WHERE number = $1 AND id > (SELECT COALESCE(MAX(id), 0) FROM order_status_history "+
+			"WHERE number = $1 AND new_status = $2) ORDER BY id", number, models.OrderCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(historyRows)
+
+	for historyRows.Next() {
+		var change models.StatusChange
+		err = historyRows.Scan(&change.OldStatus, &change.NewStatus, &change.Accrual, &change.Source, &change.Note,
+			&change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		details.History = append(details.History, change)
+	}
+
+	err = historyRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
+
+// DeleteOrder cancels the user order which isn't processed yet, the number
+// becomes free for upload again. The history is kept and closed with the
+// CANCELLED step, orders with an open dispute or claim can't be cancelled.
+func (db *DBStore) DeleteOrder(ctx context.Context, login string, number string) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	status, err := lockUserOrder(ctx, tx, login, number)
+	if err != nil {
+		return err
+	}
+
+	if status != models.OrderNew && status != models.OrderInvalid {
+		return ErrOrderNotCancellable
+	}
+
+	var openCases int
+	row := tx.QueryRowContext(ctx,
+		"SELECT (SELECT COUNT(*) FROM disputes WHERE number = $1 AND status <> $2) + "+
+			"(SELECT COUNT(*) FROM order_claims WHERE number = $1 AND status IN ($3, $4))",
+		number, models.DisputeResolved, models.ClaimPending, models.ClaimContested)
+	if err := row.Scan(&openCases); err != nil {
+		return err
+	}
+
+	if openCases > 0 {
+		return ErrOrderHasOpenCase
+	}
+
+	err = recordStatusChange(ctx, tx, number, &models.StatusChange{
+		OldStatus: status,
+		NewStatus: models.OrderCancelled,
+		Source:    models.HistorySourceUser,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE number = $1", number)
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(ctx, tx, &models.AuditEvent{
+		Login:   login,
+		Actor:   login,
+		Action:  models.AuditOrderCancelled,
+		Details: fmt.Sprintf("order %s in %s status", number, status),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,49 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		openCases   int64
+		wantErr     error
+		wantHistory [][]driver.Value
+	}{
+		{
+			name:   "Cancel new order",
+			status: models.OrderNew,
+			wantHistory: [][]driver.Value{
+				{"267876232367723", models.OrderNew, models.OrderCancelled, nil, models.HistorySourceUser, ""},
+			},
+		},
+		{name: "Processed order", status: models.OrderProcessed, wantErr: ErrOrderNotCancellable},
+		{name: "Open dispute", status: models.OrderInvalid, openCases: 1, wantErr: ErrOrderHasOpenCase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{results: []fakeResult{
+				{match: "SELECT status FROM orders", columns: []string{"status"}, rows: [][]driver.Value{{tt.status}}},
+				{match: "FROM disputes", columns: []string{"count"}, rows: [][]driver.Value{{tt.openCases}}},
+			}}
+
+			store := NewDBStore(sql.OpenDB(fake), Config{})
+			defer store.Close()
+
+			err := store.DeleteOrder(context.Background(), "test", "267876232367723")
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			assert.Empty(t, fake.execArgs("DELETE FROM order_status_history"))
+			assert.Equal(t, tt.wantHistory, fake.execArgs("INSERT INTO order_status_history"))
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/jackc/pgconn"
+)
+
+var (
+	ErrHoldNotFound  = errors.New("withdrawal hold not found")
+	ErrHoldNotActive = errors.New("withdrawal hold is not active")
+)
+
+// AuthorizeWithdraw reserves points for the withdrawal until the hold
+// is captured, voided or expired.
+func (db *DBStore) AuthorizeWithdraw(ctx context.Context, login string, withdraw *models.Withdraw) (*models.Hold, error) {
+	if err := db.cfg.Amount.Validate(withdraw.Sum); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx)
+
+	_, available, err := db.lockLots(ctx, tx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if available.LessThan(withdraw.Sum) {
+		return nil, ErrInsufficientBalance
+	}
+
+	if err := db.checkDailyAmount(ctx, tx, login, withdraw.Sum); err != nil {
+		return nil, err
+	}
+
+	if err := db.checkWithdrawalLimits(ctx, tx, login, withdraw.Sum); err != nil {
+		return nil, db.auditLimitBreach(ctx, login, err)
+	}
+
+	var orderLogin string
+	row := tx.QueryRowContext(ctx, `
+		SELECT login FROM orders WHERE number = $1
+		UNION ALL
+		SELECT login FROM withdrawal_holds WHERE number = $1 AND status = $2 AND expires_at > now()
+		LIMIT 1`, withdraw.Order, models.HoldAuthorized)
+
+	err = row.Scan(&orderLogin)
+	switch {
+	case err == nil && login != orderLogin:
+		return nil, ErrOtherOrderExists
+	case err == nil:
+		return nil, ErrOrderExists
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	hold := models.Hold{
+		Order:  withdraw.Order,
+		Sum:    withdraw.Sum,
+		Status: models.HoldAuthorized,
+	}
+
+	row = tx.QueryRowContext(ctx,
+		"INSERT INTO withdrawal_holds (login, number, sum, status, expires_at) "+
+			"VALUES ($1, $2, $3, $4, now() + $5 * interval '1 second') "+
+			"RETURNING id, created_at, expires_at",
+		login, withdraw.Order, withdraw.Sum, models.HoldAuthorized, intervalSeconds(db.cfg.HoldTTL))
+
+	err = row.Scan(&hold.ID, &hold.CreatedAt, &hold.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, tx.Commit()
+}
+
+// CaptureWithdraw turns the active hold into a regular withdrawal.
+func (db *DBStore) CaptureWithdraw(ctx context.Context, login string, holdID int64) error {
+	var pgErr *pgconn.PgError
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	hold, err := lockActiveHold(ctx, tx, login, holdID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE withdrawal_holds SET status = $1, closed_at = now() WHERE id = $2", models.HoldCaptured, hold.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := db.debit(ctx, tx, login, hold.Sum); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO orders (number, login, withdraw) VALUES ($1, $2, $3)", hold.Order, login, hold.Sum)
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation {
+		return ErrOtherOrderExists
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// VoidWithdraw releases points reserved by the active hold.
+func (db *DBStore) VoidWithdraw(ctx context.Context, login string, holdID int64) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	hold, err := lockActiveHold(ctx, tx, login, holdID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE withdrawal_holds SET status = $1, closed_at = now() WHERE id = $2", models.HoldVoided, hold.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReleaseExpiredHolds marks holds which weren't captured in time as expired.
+// It returns the number of released holds.
+func (db *DBStore) ReleaseExpiredHolds(ctx context.Context) (int64, error) {
+	result, err := db.connection.ExecContext(ctx,
+		"UPDATE withdrawal_holds SET status = $1, closed_at = expires_at WHERE status = $2 AND expires_at <= now()",
+		models.HoldExpired, models.HoldAuthorized)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func lockActiveHold(ctx context.Context, tx *sql.Tx, login string, holdID int64) (*models.Hold, error) {
+	var (
+		hold   models.Hold
+		active bool
+	)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT id, number, sum, status, created_at, expires_at, expires_at > now() "+
+			"FROM withdrawal_holds WHERE id = $1 AND login = $2 FOR UPDATE", holdID, login)
+
+	err := row.Scan(&hold.ID, &hold.Order, &hold.Sum, &hold.Status, &hold.CreatedAt, &hold.ExpiresAt, &active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Status != models.HoldAuthorized || !active {
+		return nil, ErrHoldNotActive
+	}
+
+	return &hold, nil
+}
@@ -0,0 +1,179 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrWithdrawalLimitExceeded = errors.New("withdrawal limit exceeded")
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// GetWithdrawalLimits returns the limits in effect for the user: configured
+// defaults replaced by the user's overrides.
+func (db *DBStore) GetWithdrawalLimits(ctx context.Context, login string) ([]models.WithdrawalLimit, error) {
+	return db.effectiveLimits(ctx, db.connection, login)
+}
+
+// SetWithdrawalLimits replaces the user's overrides of withdrawal limits.
+func (db *DBStore) SetWithdrawalLimits(ctx context.Context, login string, actor string,
+	limits []models.WithdrawalLimit) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	if err := userExists(ctx, tx, login); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM withdrawal_limits WHERE login = $1", login)
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range limits {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO withdrawal_limits (login, period, max_count, max_amount) VALUES ($1, $2, $3, $4)",
+			login, limit.Period, limit.MaxCount, limit.MaxAmount)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordAudit(ctx, tx, &models.AuditEvent{
+		Login:   login,
+		Actor:   actor,
+		Action:  models.AuditWithdrawalLimitsChanged,
+		Details: fmt.Sprintf("%+v", limits),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DBStore) effectiveLimits(ctx context.Context, q querier, login string) ([]models.WithdrawalLimit, error) {
+	limits := make([]models.WithdrawalLimit, 0, len(db.cfg.WithdrawalLimits))
+	overrides := make(map[string]models.WithdrawalLimit)
+
+	limitsRows, err := q.QueryContext(ctx,
+		"SELECT period, max_count, max_amount FROM withdrawal_limits WHERE login = $1 ORDER BY period", login)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(limitsRows)
+
+	for limitsRows.Next() {
+		var limit models.WithdrawalLimit
+		err = limitsRows.Scan(&limit.Period, &limit.MaxCount, &limit.MaxAmount)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides[limit.Period] = limit
+	}
+
+	err = limitsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, limit := range db.cfg.WithdrawalLimits {
+		if override, ok := overrides[limit.Period]; ok {
+			limit = override
+			delete(overrides, limit.Period)
+		}
+
+		limits = append(limits, limit)
+	}
+
+	for _, override := range overrides {
+		limits = append(limits, override)
+	}
+
+	return limits, nil
+}
+
+// checkWithdrawalLimits validates the outgoing debit against the user's limits,
+// withdrawals, active holds, transfers and redemptions within each rolling
+// window count. The caller must hold the user's lots locked.
+func (db *DBStore) checkWithdrawalLimits(ctx context.Context, tx *sql.Tx, login string, amount decimal.Decimal) error {
+	limits, err := db.effectiveLimits(ctx, tx, login)
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range limits {
+		if !limit.IsSet() {
+			continue
+		}
+
+		var (
+			count int
+			sum   decimal.Decimal
+		)
+
+		row := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM (
+				SELECT withdraw AS amount FROM orders
+				WHERE login = $1 AND withdraw IS NOT NULL AND uploaded_at > now() - $2 * interval '1 second'
+				UNION ALL
+				SELECT sum FROM withdrawal_holds
+				WHERE login = $1 AND status = $3 AND expires_at > now()
+					AND created_at > now() - $2 * interval '1 second'
+				UNION ALL
+				SELECT -amount FROM ledger
+				WHERE login = $1 AND kind = ANY($4) AND created_at > now() - $2 * interval '1 second'
+			) withdrawals`,
+			login, limit.Window().Seconds(), models.HoldAuthorized, debitKinds)
+
+		if err := row.Scan(&count, &sum); err != nil {
+			return err
+		}
+
+		if limit.MaxCount > 0 && count+1 > limit.MaxCount {
+			return fmt.Errorf("%w: %s count %d", ErrWithdrawalLimitExceeded, limit.Period, limit.MaxCount)
+		}
+
+		if limit.MaxAmount.IsPositive() && sum.Add(amount).GreaterThan(limit.MaxAmount) {
+			return fmt.Errorf("%w: %s amount %s", ErrWithdrawalLimitExceeded, limit.Period, limit.MaxAmount)
+		}
+	}
+
+	return nil
+}
+
+// auditLimitBreach records the exceeded withdrawal limit outside of the failed
+// transaction and passes the error through.
+func (db *DBStore) auditLimitBreach(ctx context.Context, login string, err error) error {
+	if !errors.Is(err, ErrWithdrawalLimitExceeded) {
+		return err
+	}
+
+	auditErr := recordAudit(ctx, db.connection, &models.AuditEvent{
+		Login:   login,
+		Action:  models.AuditWithdrawalLimitExceeded,
+		Details: err.Error(),
+	})
+	if auditErr != nil {
+		log.Error().Err(auditErr).Msgf("Couldn't record audit event for %s", login)
+	}
+
+	return err
+}
@@ -0,0 +1,70 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutgoingDebitsCheckWithdrawalLimits(t *testing.T) {
+	results := []fakeResult{
+		// one transfer within the day already
+		{match: ") withdrawals", columns: []string{"count", "sum"}, rows: [][]driver.Value{{int64(1), "100"}}},
+		{match: "SELECT login FROM users", columns: []string{"login"}, rows: [][]driver.Value{{"recipient"}}},
+		{match: "FROM accrual_lots", columns: []string{"id", "remaining"}, rows: [][]driver.Value{{int64(7), "1000"}}},
+		{match: "FROM withdrawal_holds", columns: []string{"sum"}, rows: [][]driver.Value{{"0"}}},
+		{
+			match:   "FROM rewards",
+			columns: []string{"name", "price", "stock"},
+			rows:    [][]driver.Value{{"Coffee", "150", nil}},
+		},
+	}
+
+	cfg := Config{
+		WithdrawalLimits: []models.WithdrawalLimit{{Period: models.LimitDaily, MaxCount: 1}},
+	}
+
+	tests := []struct {
+		name  string
+		debit func(store *DBStore) error
+	}{
+		{
+			name: "Transfer",
+			debit: func(store *DBStore) error {
+				return store.Transfer(context.Background(), "test",
+					&models.Transfer{Login: "recipient", Sum: decimal.NewFromInt(100)})
+			},
+		},
+		{
+			name: "Redeem",
+			debit: func(store *DBStore) error {
+				_, err := store.Redeem(context.Background(), "test", 1)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{results: results}
+
+			store := NewDBStore(sql.OpenDB(fake), cfg)
+			defer store.Close()
+
+			err := tt.debit(store)
+			require.ErrorIs(t, err, ErrWithdrawalLimitExceeded)
+
+			audit := fake.execArgs("INSERT INTO audit_events")
+			require.Len(t, audit, 1)
+			assert.Contains(t, audit[0], models.AuditWithdrawalLimitExceeded)
+			assert.Empty(t, fake.execArgs("INSERT INTO ledger"))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type lot struct {
+	id        int64
+	remaining decimal.Decimal
+}
+
+// credit stores points as a new lot which expires after the configured TTL.
+func (db *DBStore) credit(ctx context.Context, tx *sql.Tx, login string, source string, amount decimal.Decimal) error {
+	return db.creditMaturing(ctx, tx, login, source, amount, 0)
+}
+
+// creditMaturing stores points as a new lot which can't be spent until the
+// maturation period passes, zero period makes it spendable at once.
+func (db *DBStore) creditMaturing(ctx context.Context, tx *sql.Tx, login string, source string,
+	amount decimal.Decimal, maturation time.Duration) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO accrual_lots (login, source, amount, remaining, expires_at, available_at) "+
+			"VALUES ($1, $2, $3, $3, now() + $4 * interval '1 second', now() + $5 * interval '1 second')",
+		login, source, amount, intervalSeconds(db.cfg.PointsTTL), intervalSeconds(maturation))
+
+	return err
+}
+
+// debit spends points from the oldest lots first.
+func (db *DBStore) debit(ctx context.Context, tx *sql.Tx, login string, amount decimal.Decimal) error {
+	lots, available, err := db.lockLots(ctx, tx, login)
+	if err != nil {
+		return err
+	}
+
+	if available.LessThan(amount) {
+		return ErrInsufficientBalance
+	}
+
+	return consumeLots(ctx, tx, lots, amount)
+}
+
+// consumeLots takes amount from the lots in the given order.
+func consumeLots(ctx context.Context, tx *sql.Tx, lots []lot, amount decimal.Decimal) error {
+	left := amount
+	for _, l := range lots {
+		if !left.IsPositive() {
+			break
+		}
+
+		spent := decimal.Min(l.remaining, left)
+		_, err := tx.ExecContext(ctx,
+			"UPDATE accrual_lots SET remaining = remaining - $1 WHERE id = $2", spent, l.id)
+		if err != nil {
+			return err
+		}
+
+		left = left.Sub(spent)
+	}
+
+	return nil
+}
+
+// lockLots locks spendable lots of the user in FIFO order and returns them
+// together with the amount available for spending, i.e. without held and
+// maturing points.
+func (db *DBStore) lockLots(ctx context.Context, tx *sql.Tx, login string) ([]lot, decimal.Decimal, error) {
+	lots, err := queryLots(ctx, tx,
+		"SELECT id, remaining FROM accrual_lots "+
+			"WHERE login = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > now()) "+
+			"AND (available_at IS NULL OR available_at <= now()) "+
+			"ORDER BY accrued_at, id FOR UPDATE", login)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
+	available, err := availablePoints(ctx, tx, login, lots)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
+	return lots, available, nil
+}
+
+// lockOwnedLots locks all unexpired lots of the user in FIFO order, maturing
+// ones included, and returns them together with their amount without held points.
+func lockOwnedLots(ctx context.Context, tx *sql.Tx, login string) ([]lot, decimal.Decimal, error) {
+	lots, err := queryLots(ctx, tx,
+		"SELECT id, remaining FROM accrual_lots "+
+			"WHERE login = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > now()) "+
+			"ORDER BY accrued_at, id FOR UPDATE", login)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
+	available, err := availablePoints(ctx, tx, login, lots)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
+	return lots, available, nil
+}
+
+// availablePoints sums the lots and takes the held points off.
+func availablePoints(ctx context.Context, tx *sql.Tx, login string, lots []lot) (decimal.Decimal, error) {
+	available := decimal.Zero
+	for _, l := range lots {
+		available = available.Add(l.remaining)
+	}
+
+	var locked decimal.Decimal
+	row := tx.QueryRowContext(ctx,
+		"SELECT COALESCE(SUM(sum), 0) FROM withdrawal_holds "+
+			"WHERE login = $1 AND status = $2 AND expires_at > now()", login, models.HoldAuthorized)
+	if err := row.Scan(&locked); err != nil {
+		return decimal.Zero, err
+	}
+
+	return available.Sub(locked), nil
+}
+
+func queryLots(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]lot, error) {
+	lotsRows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(lotsRows)
+
+	lots := make([]lot, 0)
+	for lotsRows.Next() {
+		var l lot
+		err = lotsRows.Scan(&l.id, &l.remaining)
+		if err != nil {
+			return nil, err
+		}
+
+		lots = append(lots, l)
+	}
+
+	err = lotsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+func (db *DBStore) GetLedger(ctx context.Context, login string) ([]models.LedgerEntry, error) {
+	entries := make([]models.LedgerEntry, 0)
+
+	ledgerRows, err := db.connection.QueryContext(ctx,
+		"SELECT kind,amount,COALESCE(reference, ''),created_at FROM ledger WHERE login = $1 ORDER BY created_at, id",
+		login)
+
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(ledgerRows)
+
+	for ledgerRows.Next() {
+		var entry models.LedgerEntry
+		err = ledgerRows.Scan(&entry.Kind, &entry.Amount, &entry.Reference, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	err = ledgerRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExpirePoints zeroes the lots which are past their expiry date and records
+// the forfeited points in the ledger. It returns the number of expired lots.
+func (db *DBStore) ExpirePoints(ctx context.Context) (int64, error) {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(tx)
+
+	result, err := tx.ExecContext(ctx, `
+		WITH expired AS (
+			SELECT id, login, source, remaining FROM accrual_lots
+			WHERE remaining > 0 AND expires_at <= now()
+			FOR UPDATE
+		), cleared AS (
+			UPDATE accrual_lots SET remaining = 0 FROM expired WHERE accrual_lots.id = expired.id
+		)
+		INSERT INTO ledger (login, kind, amount, reference)
+		SELECT login, $1, -remaining, source FROM expired`,
+		models.LedgerExpiration)
+	if err != nil {
+		return 0, err
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, tx.Commit()
+}
+
+// intervalSeconds converts duration to seconds for interval arithmetic,
+// zero duration becomes NULL so the resulting timestamp is NULL as well.
+func intervalSeconds(d time.Duration) interface{} {
+	if d <= 0 {
+		return nil
+	}
+
+	return d.Seconds()
+}
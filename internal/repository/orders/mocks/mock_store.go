@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/go-rfe/loyalty-system/internal/models"
 	gomock "github.com/golang/mock/gomock"
@@ -35,33 +36,397 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// Adjust mocks base method.
+func (m *MockStore) Adjust(arg0 context.Context, arg1 *models.Adjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockStoreMockRecorder) Adjust(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockStore)(nil).Adjust), arg0, arg1)
+}
+
+// AssignDispute mocks base method.
+func (m *MockStore) AssignDispute(arg0 context.Context, arg1 int64, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignDispute", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignDispute indicates an expected call of AssignDispute.
+func (mr *MockStoreMockRecorder) AssignDispute(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignDispute", reflect.TypeOf((*MockStore)(nil).AssignDispute), arg0, arg1, arg2, arg3)
+}
+
+// AuthorizeWithdraw mocks base method.
+func (m *MockStore) AuthorizeWithdraw(arg0 context.Context, arg1 string, arg2 *models.Withdraw) (*models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeWithdraw", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeWithdraw indicates an expected call of AuthorizeWithdraw.
+func (mr *MockStoreMockRecorder) AuthorizeWithdraw(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeWithdraw", reflect.TypeOf((*MockStore)(nil).AuthorizeWithdraw), arg0, arg1, arg2)
+}
+
+// CaptureWithdraw mocks base method.
+func (m *MockStore) CaptureWithdraw(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureWithdraw", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CaptureWithdraw indicates an expected call of CaptureWithdraw.
+func (mr *MockStoreMockRecorder) CaptureWithdraw(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureWithdraw", reflect.TypeOf((*MockStore)(nil).CaptureWithdraw), arg0, arg1, arg2)
+}
+
+// ContestClaim mocks base method.
+func (m *MockStore) ContestClaim(arg0 context.Context, arg1 string, arg2 int64, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContestClaim", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ContestClaim indicates an expected call of ContestClaim.
+func (mr *MockStoreMockRecorder) ContestClaim(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContestClaim", reflect.TypeOf((*MockStore)(nil).ContestClaim), arg0, arg1, arg2, arg3)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockStore) CreateCampaign(arg0 context.Context, arg1 *models.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockStoreMockRecorder) CreateCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockStore)(nil).CreateCampaign), arg0, arg1)
+}
+
+// CreateClaim mocks base method.
+func (m *MockStore) CreateClaim(arg0 context.Context, arg1 *models.Claim) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClaim", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClaim indicates an expected call of CreateClaim.
+func (mr *MockStoreMockRecorder) CreateClaim(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClaim", reflect.TypeOf((*MockStore)(nil).CreateClaim), arg0, arg1)
+}
+
 // CreateOrder mocks base method.
-func (m *MockStore) CreateOrder(arg0 context.Context, arg1, arg2 string) error {
+func (m *MockStore) CreateOrder(arg0 context.Context, arg1, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockStoreMockRecorder) CreateOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) CreateOrder(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), arg0, arg1, arg2, arg3)
 }
 
-// GetOrders mocks base method.
-func (m *MockStore) GetOrders(arg0 context.Context, arg1 string) ([]models.Order, error) {
+// CreatePromoBatch mocks base method.
+func (m *MockStore) CreatePromoBatch(arg0 context.Context, arg1 *models.PromoBatch) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrders", arg0, arg1)
-	ret0, _ := ret[0].([]models.Order)
+	ret := m.ctrl.Call(m, "CreatePromoBatch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePromoBatch indicates an expected call of CreatePromoBatch.
+func (mr *MockStoreMockRecorder) CreatePromoBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromoBatch", reflect.TypeOf((*MockStore)(nil).CreatePromoBatch), arg0, arg1)
+}
+
+// CreateReward mocks base method.
+func (m *MockStore) CreateReward(arg0 context.Context, arg1 *models.Reward) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReward", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReward indicates an expected call of CreateReward.
+func (mr *MockStoreMockRecorder) CreateReward(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReward", reflect.TypeOf((*MockStore)(nil).CreateReward), arg0, arg1)
+}
+
+// DecideClaim mocks base method.
+func (m *MockStore) DecideClaim(arg0 context.Context, arg1 int64, arg2 bool, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideClaim", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecideClaim indicates an expected call of DecideClaim.
+func (mr *MockStoreMockRecorder) DecideClaim(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideClaim", reflect.TypeOf((*MockStore)(nil).DecideClaim), arg0, arg1, arg2, arg3)
+}
+
+// DecideReview mocks base method.
+func (m *MockStore) DecideReview(arg0 context.Context, arg1 string, arg2 bool, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideReview", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecideReview indicates an expected call of DecideReview.
+func (mr *MockStoreMockRecorder) DecideReview(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideReview", reflect.TypeOf((*MockStore)(nil).DecideReview), arg0, arg1, arg2, arg3)
+}
+
+// DeleteOrder mocks base method.
+func (m *MockStore) DeleteOrder(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrder indicates an expected call of DeleteOrder.
+func (mr *MockStoreMockRecorder) DeleteOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockStore)(nil).DeleteOrder), arg0, arg1, arg2)
+}
+
+// DowngradeTiers mocks base method.
+func (m *MockStore) DowngradeTiers(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DowngradeTiers", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DowngradeTiers indicates an expected call of DowngradeTiers.
+func (mr *MockStoreMockRecorder) DowngradeTiers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DowngradeTiers", reflect.TypeOf((*MockStore)(nil).DowngradeTiers), arg0)
+}
+
+// ExpirePoints mocks base method.
+func (m *MockStore) ExpirePoints(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePoints", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePoints indicates an expected call of ExpirePoints.
+func (mr *MockStoreMockRecorder) ExpirePoints(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePoints", reflect.TypeOf((*MockStore)(nil).ExpirePoints), arg0)
+}
+
+// FlagOrder mocks base method.
+func (m *MockStore) FlagOrder(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlagOrder indicates an expected call of FlagOrder.
+func (mr *MockStoreMockRecorder) FlagOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagOrder", reflect.TypeOf((*MockStore)(nil).FlagOrder), arg0, arg1, arg2)
+}
+
+// GetAdjustments mocks base method.
+func (m *MockStore) GetAdjustments(arg0 context.Context, arg1 string) ([]models.Adjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdjustments", arg0, arg1)
+	ret0, _ := ret[0].([]models.Adjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdjustments indicates an expected call of GetAdjustments.
+func (mr *MockStoreMockRecorder) GetAdjustments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdjustments", reflect.TypeOf((*MockStore)(nil).GetAdjustments), arg0, arg1)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockStore) GetAuditEvents(arg0 context.Context, arg1 string) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", arg0, arg1)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockStoreMockRecorder) GetAuditEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockStore)(nil).GetAuditEvents), arg0, arg1)
+}
+
+// GetBalance mocks base method.
+func (m *MockStore) GetBalance(arg0 context.Context, arg1 string) (*models.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0, arg1)
+	ret0, _ := ret[0].(*models.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockStoreMockRecorder) GetBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockStore)(nil).GetBalance), arg0, arg1)
+}
+
+// GetBalanceAsOf mocks base method.
+func (m *MockStore) GetBalanceAsOf(arg0 context.Context, arg1 string, arg2 time.Time) (*models.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAsOf", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAsOf indicates an expected call of GetBalanceAsOf.
+func (mr *MockStoreMockRecorder) GetBalanceAsOf(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAsOf", reflect.TypeOf((*MockStore)(nil).GetBalanceAsOf), arg0, arg1, arg2)
+}
+
+// GetCampaignReport mocks base method.
+func (m *MockStore) GetCampaignReport(arg0 context.Context, arg1 int64) (*models.CampaignReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignReport", arg0, arg1)
+	ret0, _ := ret[0].(*models.CampaignReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignReport indicates an expected call of GetCampaignReport.
+func (mr *MockStoreMockRecorder) GetCampaignReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignReport", reflect.TypeOf((*MockStore)(nil).GetCampaignReport), arg0, arg1)
+}
+
+// GetCampaigns mocks base method.
+func (m *MockStore) GetCampaigns(arg0 context.Context) ([]models.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaigns", arg0)
+	ret0, _ := ret[0].([]models.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaigns indicates an expected call of GetCampaigns.
+func (mr *MockStoreMockRecorder) GetCampaigns(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaigns", reflect.TypeOf((*MockStore)(nil).GetCampaigns), arg0)
+}
+
+// GetClaims mocks base method.
+func (m *MockStore) GetClaims(arg0 context.Context, arg1 string) ([]models.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", arg0, arg1)
+	ret0, _ := ret[0].([]models.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockStoreMockRecorder) GetClaims(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockStore)(nil).GetClaims), arg0, arg1)
+}
+
+// GetDisputes mocks base method.
+func (m *MockStore) GetDisputes(arg0 context.Context, arg1 string) ([]models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputes indicates an expected call of GetDisputes.
+func (mr *MockStoreMockRecorder) GetDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputes", reflect.TypeOf((*MockStore)(nil).GetDisputes), arg0, arg1)
+}
+
+// GetLedger mocks base method.
+func (m *MockStore) GetLedger(arg0 context.Context, arg1 string) ([]models.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedger", arg0, arg1)
+	ret0, _ := ret[0].([]models.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedger indicates an expected call of GetLedger.
+func (mr *MockStoreMockRecorder) GetLedger(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedger", reflect.TypeOf((*MockStore)(nil).GetLedger), arg0, arg1)
+}
+
+// GetOrder mocks base method.
+func (m *MockStore) GetOrder(arg0 context.Context, arg1, arg2 string) (*models.OrderDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.OrderDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockStoreMockRecorder) GetOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockStore)(nil).GetOrder), arg0, arg1, arg2)
+}
+
+// GetOrders mocks base method.
+func (m *MockStore) GetOrders(arg0 context.Context, arg1 string, arg2 models.OrdersFilter) ([]models.Order, *models.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetOrders indicates an expected call of GetOrders.
-func (mr *MockStoreMockRecorder) GetOrders(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetOrders(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockStore)(nil).GetOrders), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockStore)(nil).GetOrders), arg0, arg1, arg2)
 }
 
 // GetProcessedOrders mocks base method.
@@ -79,6 +444,112 @@ func (mr *MockStoreMockRecorder) GetProcessedOrders(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProcessedOrders", reflect.TypeOf((*MockStore)(nil).GetProcessedOrders), arg0, arg1)
 }
 
+// GetPromoBatch mocks base method.
+func (m *MockStore) GetPromoBatch(arg0 context.Context, arg1 int64) (*models.PromoBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoBatch", arg0, arg1)
+	ret0, _ := ret[0].(*models.PromoBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoBatch indicates an expected call of GetPromoBatch.
+func (mr *MockStoreMockRecorder) GetPromoBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoBatch", reflect.TypeOf((*MockStore)(nil).GetPromoBatch), arg0, arg1)
+}
+
+// GetRedemptions mocks base method.
+func (m *MockStore) GetRedemptions(arg0 context.Context, arg1 string) ([]models.Redemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedemptions", arg0, arg1)
+	ret0, _ := ret[0].([]models.Redemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedemptions indicates an expected call of GetRedemptions.
+func (mr *MockStoreMockRecorder) GetRedemptions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedemptions", reflect.TypeOf((*MockStore)(nil).GetRedemptions), arg0, arg1)
+}
+
+// GetReferrals mocks base method.
+func (m *MockStore) GetReferrals(arg0 context.Context, arg1 string) (*models.Referrals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferrals", arg0, arg1)
+	ret0, _ := ret[0].(*models.Referrals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferrals indicates an expected call of GetReferrals.
+func (mr *MockStoreMockRecorder) GetReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferrals", reflect.TypeOf((*MockStore)(nil).GetReferrals), arg0, arg1)
+}
+
+// GetReviewQueue mocks base method.
+func (m *MockStore) GetReviewQueue(arg0 context.Context) ([]models.HeldAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewQueue", arg0)
+	ret0, _ := ret[0].([]models.HeldAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewQueue indicates an expected call of GetReviewQueue.
+func (mr *MockStoreMockRecorder) GetReviewQueue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewQueue", reflect.TypeOf((*MockStore)(nil).GetReviewQueue), arg0)
+}
+
+// GetRewards mocks base method.
+func (m *MockStore) GetRewards(arg0 context.Context, arg1 bool) ([]models.Reward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewards", arg0, arg1)
+	ret0, _ := ret[0].([]models.Reward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewards indicates an expected call of GetRewards.
+func (mr *MockStoreMockRecorder) GetRewards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewards", reflect.TypeOf((*MockStore)(nil).GetRewards), arg0, arg1)
+}
+
+// GetStatement mocks base method.
+func (m *MockStore) GetStatement(arg0 context.Context, arg1 string, arg2 models.Page) ([]models.StatementLine, *models.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.StatementLine)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockStoreMockRecorder) GetStatement(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockStore)(nil).GetStatement), arg0, arg1, arg2)
+}
+
+// GetTier mocks base method.
+func (m *MockStore) GetTier(arg0 context.Context, arg1 string) (*models.TierStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTier", arg0, arg1)
+	ret0, _ := ret[0].(*models.TierStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTier indicates an expected call of GetTier.
+func (mr *MockStoreMockRecorder) GetTier(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTier", reflect.TypeOf((*MockStore)(nil).GetTier), arg0, arg1)
+}
+
 // GetUnprocessedOrders mocks base method.
 func (m *MockStore) GetUnprocessedOrders(arg0 context.Context) ([]models.Order, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +565,21 @@ func (mr *MockStoreMockRecorder) GetUnprocessedOrders(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnprocessedOrders", reflect.TypeOf((*MockStore)(nil).GetUnprocessedOrders), arg0)
 }
 
+// GetWithdrawalLimits mocks base method.
+func (m *MockStore) GetWithdrawalLimits(arg0 context.Context, arg1 string) ([]models.WithdrawalLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawalLimits", arg0, arg1)
+	ret0, _ := ret[0].([]models.WithdrawalLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithdrawalLimits indicates an expected call of GetWithdrawalLimits.
+func (mr *MockStoreMockRecorder) GetWithdrawalLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawalLimits", reflect.TypeOf((*MockStore)(nil).GetWithdrawalLimits), arg0, arg1)
+}
+
 // GetWithdrawals mocks base method.
 func (m *MockStore) GetWithdrawals(arg0 context.Context, arg1 string) ([]models.Withdraw, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +595,197 @@ func (mr *MockStoreMockRecorder) GetWithdrawals(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockStore)(nil).GetWithdrawals), arg0, arg1)
 }
 
+// ListClaims mocks base method.
+func (m *MockStore) ListClaims(arg0 context.Context, arg1 string) ([]models.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClaims", arg0, arg1)
+	ret0, _ := ret[0].([]models.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClaims indicates an expected call of ListClaims.
+func (mr *MockStoreMockRecorder) ListClaims(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClaims", reflect.TypeOf((*MockStore)(nil).ListClaims), arg0, arg1)
+}
+
+// ListDisputes mocks base method.
+func (m *MockStore) ListDisputes(arg0 context.Context, arg1 string) ([]models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisputes indicates an expected call of ListDisputes.
+func (mr *MockStoreMockRecorder) ListDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisputes", reflect.TypeOf((*MockStore)(nil).ListDisputes), arg0, arg1)
+}
+
+// OpenDispute mocks base method.
+func (m *MockStore) OpenDispute(arg0 context.Context, arg1 *models.Dispute) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDispute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OpenDispute indicates an expected call of OpenDispute.
+func (mr *MockStoreMockRecorder) OpenDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDispute", reflect.TypeOf((*MockStore)(nil).OpenDispute), arg0, arg1)
+}
+
+// PurgeUploadAttempts mocks base method.
+func (m *MockStore) PurgeUploadAttempts(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUploadAttempts", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUploadAttempts indicates an expected call of PurgeUploadAttempts.
+func (mr *MockStoreMockRecorder) PurgeUploadAttempts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUploadAttempts", reflect.TypeOf((*MockStore)(nil).PurgeUploadAttempts), arg0)
+}
+
+// Redeem mocks base method.
+func (m *MockStore) Redeem(arg0 context.Context, arg1 string, arg2 int64) (*models.Redemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Redemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockStoreMockRecorder) Redeem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockStore)(nil).Redeem), arg0, arg1, arg2)
+}
+
+// RedeemPromoCode mocks base method.
+func (m *MockStore) RedeemPromoCode(arg0 context.Context, arg1, arg2, arg3 string) (*models.PromoRedemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemPromoCode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.PromoRedemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemPromoCode indicates an expected call of RedeemPromoCode.
+func (mr *MockStoreMockRecorder) RedeemPromoCode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemPromoCode", reflect.TypeOf((*MockStore)(nil).RedeemPromoCode), arg0, arg1, arg2, arg3)
+}
+
+// ReleaseExpiredHolds mocks base method.
+func (m *MockStore) ReleaseExpiredHolds(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredHolds", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredHolds indicates an expected call of ReleaseExpiredHolds.
+func (mr *MockStoreMockRecorder) ReleaseExpiredHolds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredHolds", reflect.TypeOf((*MockStore)(nil).ReleaseExpiredHolds), arg0)
+}
+
+// ResolveDispute mocks base method.
+func (m *MockStore) ResolveDispute(arg0 context.Context, arg1 int64, arg2 *models.DisputeResolution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDispute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveDispute indicates an expected call of ResolveDispute.
+func (mr *MockStoreMockRecorder) ResolveDispute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDispute", reflect.TypeOf((*MockStore)(nil).ResolveDispute), arg0, arg1, arg2)
+}
+
+// ReverseCampaign mocks base method.
+func (m *MockStore) ReverseCampaign(arg0 context.Context, arg1 int64) (*models.CampaignReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseCampaign", arg0, arg1)
+	ret0, _ := ret[0].(*models.CampaignReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseCampaign indicates an expected call of ReverseCampaign.
+func (mr *MockStoreMockRecorder) ReverseCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseCampaign", reflect.TypeOf((*MockStore)(nil).ReverseCampaign), arg0, arg1)
+}
+
+// ScreenUpload mocks base method.
+func (m *MockStore) ScreenUpload(arg0 context.Context, arg1 *models.UploadAttempt) (*models.FraudDecision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScreenUpload", arg0, arg1)
+	ret0, _ := ret[0].(*models.FraudDecision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScreenUpload indicates an expected call of ScreenUpload.
+func (mr *MockStoreMockRecorder) ScreenUpload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScreenUpload", reflect.TypeOf((*MockStore)(nil).ScreenUpload), arg0, arg1)
+}
+
+// SetWithdrawalLimits mocks base method.
+func (m *MockStore) SetWithdrawalLimits(arg0 context.Context, arg1, arg2 string, arg3 []models.WithdrawalLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithdrawalLimits", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithdrawalLimits indicates an expected call of SetWithdrawalLimits.
+func (mr *MockStoreMockRecorder) SetWithdrawalLimits(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithdrawalLimits", reflect.TypeOf((*MockStore)(nil).SetWithdrawalLimits), arg0, arg1, arg2, arg3)
+}
+
+// Transfer mocks base method.
+func (m *MockStore) Transfer(arg0 context.Context, arg1 string, arg2 *models.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockStoreMockRecorder) Transfer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockStore)(nil).Transfer), arg0, arg1, arg2)
+}
+
+// TransferClaimedOrders mocks base method.
+func (m *MockStore) TransferClaimedOrders(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferClaimedOrders", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferClaimedOrders indicates an expected call of TransferClaimedOrders.
+func (mr *MockStoreMockRecorder) TransferClaimedOrders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferClaimedOrders", reflect.TypeOf((*MockStore)(nil).TransferClaimedOrders), arg0)
+}
+
 // UpdateOrder mocks base method.
 func (m *MockStore) UpdateOrder(arg0 context.Context, arg1 *models.Order) error {
 	m.ctrl.T.Helper()
@@ -123,6 +800,34 @@ func (mr *MockStoreMockRecorder) UpdateOrder(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockStore)(nil).UpdateOrder), arg0, arg1)
 }
 
+// UpdateReward mocks base method.
+func (m *MockStore) UpdateReward(arg0 context.Context, arg1 *models.Reward) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReward", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReward indicates an expected call of UpdateReward.
+func (mr *MockStoreMockRecorder) UpdateReward(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReward", reflect.TypeOf((*MockStore)(nil).UpdateReward), arg0, arg1)
+}
+
+// VoidWithdraw mocks base method.
+func (m *MockStore) VoidWithdraw(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidWithdraw", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VoidWithdraw indicates an expected call of VoidWithdraw.
+func (mr *MockStoreMockRecorder) VoidWithdraw(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidWithdraw", reflect.TypeOf((*MockStore)(nil).VoidWithdraw), arg0, arg1, arg2)
+}
+
 // Withdraw mocks base method.
 func (m *MockStore) Withdraw(arg0 context.Context, arg1 string, arg2 *models.Withdraw) error {
 	m.ctrl.T.Helper()
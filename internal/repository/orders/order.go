@@ -3,21 +3,90 @@ package orders
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-rfe/loyalty-system/internal/models"
 )
 
 var (
-	ErrOrderExists      = errors.New("order already exists")
-	ErrOtherOrderExists = errors.New("other user order already exists")
+	ErrOrderExists         = errors.New("order already exists")
+	ErrOtherOrderExists    = errors.New("other user order already exists")
+	ErrInsufficientBalance = errors.New("insufficient balance")
 )
 
+type Config struct {
+	PointsTTL     time.Duration
+	ExpiryWarning time.Duration
+	HoldTTL       time.Duration
+	Transfer      TransferLimits
+	Amount        models.AmountPolicy
+	Tier          TierConfig
+	Referral      ReferralBonus
+	Promo         PromoAttempts
+	ClaimWindow   time.Duration
+	Fraud         models.FraudRules
+	Review        models.ReviewPolicy
+	Maturation    models.MaturationPeriods
+
+	WithdrawalLimits []models.WithdrawalLimit
+}
+
 type Store interface {
-	CreateOrder(ctx context.Context, login string, order string) error
+	CreateOrder(ctx context.Context, login string, order string, partner string) error
 	UpdateOrder(ctx context.Context, order *models.Order) error
-	GetOrders(ctx context.Context, login string) ([]models.Order, error)
+	GetOrder(ctx context.Context, login string, number string) (*models.OrderDetails, error)
+	DeleteOrder(ctx context.Context, login string, number string) error
+	GetOrders(ctx context.Context, login string,
+		filter models.OrdersFilter) ([]models.Order, *models.Cursor, error)
 	GetUnprocessedOrders(ctx context.Context) ([]models.Order, error)
 	GetProcessedOrders(ctx context.Context, login string) ([]models.Order, error)
 	Withdraw(ctx context.Context, login string, withdraw *models.Withdraw) error
+	AuthorizeWithdraw(ctx context.Context, login string, withdraw *models.Withdraw) (*models.Hold, error)
+	CaptureWithdraw(ctx context.Context, login string, holdID int64) error
+	VoidWithdraw(ctx context.Context, login string, holdID int64) error
+	ReleaseExpiredHolds(ctx context.Context) (int64, error)
+	GetWithdrawalLimits(ctx context.Context, login string) ([]models.WithdrawalLimit, error)
+	SetWithdrawalLimits(ctx context.Context, login string, actor string, limits []models.WithdrawalLimit) error
+	GetAuditEvents(ctx context.Context, login string) ([]models.AuditEvent, error)
 	GetWithdrawals(ctx context.Context, login string) ([]models.Withdraw, error)
+	Transfer(ctx context.Context, login string, transfer *models.Transfer) error
+	Adjust(ctx context.Context, adjustment *models.Adjustment) error
+	GetAdjustments(ctx context.Context, login string) ([]models.Adjustment, error)
+	GetLedger(ctx context.Context, login string) ([]models.LedgerEntry, error)
+	GetBalance(ctx context.Context, login string) (*models.Balance, error)
+	GetBalanceAsOf(ctx context.Context, login string, asOf time.Time) (*models.Balance, error)
+	GetStatement(ctx context.Context, login string,
+		page models.Page) ([]models.StatementLine, *models.Cursor, error)
+	ExpirePoints(ctx context.Context) (int64, error)
+	GetTier(ctx context.Context, login string) (*models.TierStatus, error)
+	DowngradeTiers(ctx context.Context) (int64, error)
+	CreateCampaign(ctx context.Context, campaign *models.Campaign) error
+	GetCampaigns(ctx context.Context) ([]models.Campaign, error)
+	GetCampaignReport(ctx context.Context, campaignID int64) (*models.CampaignReport, error)
+	ReverseCampaign(ctx context.Context, campaignID int64) (*models.CampaignReport, error)
+	GetReferrals(ctx context.Context, login string) (*models.Referrals, error)
+	GetRewards(ctx context.Context, all bool) ([]models.Reward, error)
+	CreateReward(ctx context.Context, reward *models.Reward) error
+	UpdateReward(ctx context.Context, reward *models.Reward) error
+	Redeem(ctx context.Context, login string, rewardID int64) (*models.Redemption, error)
+	GetRedemptions(ctx context.Context, login string) ([]models.Redemption, error)
+	CreatePromoBatch(ctx context.Context, batch *models.PromoBatch) error
+	GetPromoBatch(ctx context.Context, batchID int64) (*models.PromoBatch, error)
+	RedeemPromoCode(ctx context.Context, login string, ip string, code string) (*models.PromoRedemption, error)
+	OpenDispute(ctx context.Context, dispute *models.Dispute) error
+	GetDisputes(ctx context.Context, login string) ([]models.Dispute, error)
+	ListDisputes(ctx context.Context, status string) ([]models.Dispute, error)
+	AssignDispute(ctx context.Context, id int64, assignee string, actor string) error
+	ResolveDispute(ctx context.Context, id int64, resolution *models.DisputeResolution) error
+	CreateClaim(ctx context.Context, claim *models.Claim) error
+	GetClaims(ctx context.Context, login string) ([]models.Claim, error)
+	ListClaims(ctx context.Context, status string) ([]models.Claim, error)
+	ContestClaim(ctx context.Context, login string, id int64, comment string) error
+	DecideClaim(ctx context.Context, id int64, approve bool, decider string) error
+	TransferClaimedOrders(ctx context.Context) (int64, error)
+	ScreenUpload(ctx context.Context, attempt *models.UploadAttempt) (*models.FraudDecision, error)
+	FlagOrder(ctx context.Context, number string, reason string) error
+	PurgeUploadAttempts(ctx context.Context) (int64, error)
+	GetReviewQueue(ctx context.Context) ([]models.HeldAccrual, error)
+	DecideReview(ctx context.Context, number string, approve bool, reviewer string) error
 }
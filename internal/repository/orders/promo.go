@@ -0,0 +1,268 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/jackc/pgconn"
+)
+
+var (
+	ErrPromoBatchNotFound   = errors.New("promo batch not found")
+	ErrPromoCodeInvalid     = errors.New("promo code is invalid")
+	ErrPromoCodeExpired     = errors.New("promo code has expired")
+	ErrPromoCodeUsedUp      = errors.New("promo code has been used up")
+	ErrPromoCodeRedeemed    = errors.New("promo code has been already redeemed")
+	ErrPromoTooManyAttempts = errors.New("too many failed promo code attempts")
+)
+
+// PromoAttempts limits failed redemptions of the user and of the client
+// address within the window to stop codes brute-forcing, zero limits mean
+// no limit.
+type PromoAttempts struct {
+	MaxFailures   int
+	MaxIPFailures int
+	Window        time.Duration
+}
+
+// IsSet tells if any limit is configured.
+func (p PromoAttempts) IsSet() bool {
+	return p.MaxFailures > 0 || p.MaxIPFailures > 0
+}
+
+func (db *DBStore) CreatePromoBatch(ctx context.Context, batch *models.PromoBatch) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var expiresAt interface{}
+	if batch.ExpiresAt != nil {
+		expiresAt = batch.ExpiresAt.UTC()
+	}
+
+	row := tx.QueryRowContext(ctx,
+		"INSERT INTO promo_batches (name, amount, max_uses, expires_at, created_by) VALUES ($1, $2, $3, $4, $5) "+
+			"RETURNING id, created_at",
+		batch.Name, batch.Amount, batch.MaxUses, expiresAt, batch.CreatedBy)
+
+	err = row.Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, 0, batch.Count)
+	batch.Codes = make([]models.PromoCode, 0, batch.Count)
+	for i := 0; i < batch.Count; i++ {
+		code, err := models.NewPromoCode()
+		if err != nil {
+			return err
+		}
+
+		codes = append(codes, code)
+		batch.Codes = append(batch.Codes, models.PromoCode{Code: code})
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO promo_codes (code, batch_id) SELECT unnest($1::text[]), $2", codes, batch.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPromoBatch returns the batch together with its codes.
+func (db *DBStore) GetPromoBatch(ctx context.Context, batchID int64) (*models.PromoBatch, error) {
+	batch := models.PromoBatch{ID: batchID, Codes: make([]models.PromoCode, 0)}
+
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT name, amount, max_uses, expires_at, created_by, created_at FROM promo_batches WHERE id = $1",
+		batchID)
+
+	err := row.Scan(&batch.Name, &batch.Amount, &batch.MaxUses, &batch.ExpiresAt, &batch.CreatedBy, &batch.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPromoBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	codesRows, err := db.connection.QueryContext(ctx,
+		"SELECT code, uses FROM promo_codes WHERE batch_id = $1 ORDER BY code", batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(codesRows)
+
+	for codesRows.Next() {
+		var code models.PromoCode
+		err = codesRows.Scan(&code.Code, &code.Uses)
+		if err != nil {
+			return nil, err
+		}
+
+		batch.Codes = append(batch.Codes, code)
+	}
+
+	err = codesRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Count = len(batch.Codes)
+
+	return &batch, nil
+}
+
+// RedeemPromoCode credits the user with the points of the code. Failed attempts
+// are audited and counted against the user and the client address.
+func (db *DBStore) RedeemPromoCode(ctx context.Context, login string, ip string,
+	code string) (*models.PromoRedemption, error) {
+	attemptID, err := db.checkPromoAttempts(ctx, login, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption, err := db.redeemPromoCode(ctx, login, code)
+	failed := errors.Is(err, ErrPromoCodeInvalid) || errors.Is(err, ErrPromoCodeExpired) ||
+		errors.Is(err, ErrPromoCodeUsedUp) || errors.Is(err, ErrPromoCodeRedeemed)
+
+	if failed {
+		auditErr := recordAudit(ctx, db.connection, &models.AuditEvent{
+			Login:   login,
+			Action:  models.AuditPromoCodeFailed,
+			Details: fmt.Sprintf("%s from %s", err, ip),
+		})
+		if auditErr != nil {
+			log.Error().Err(auditErr).Msgf("Couldn't record audit event for %s", login)
+		}
+	}
+
+	db.closePromoAttempt(ctx, attemptID, !failed)
+
+	return redemption, err
+}
+
+func (db *DBStore) redeemPromoCode(ctx context.Context, login string, code string) (*models.PromoRedemption, error) {
+	var (
+		pgErr         *pgconn.PgError
+		uses, maxUses int
+		expired       bool
+	)
+
+	redemption := models.PromoRedemption{Code: code}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT c.uses, b.max_uses, b.amount, COALESCE(b.expires_at <= now(), FALSE) "+
+			"FROM promo_codes c JOIN promo_batches b ON b.id = c.batch_id WHERE c.code = $1 FOR UPDATE OF c", code)
+
+	err = row.Scan(&uses, &maxUses, &redemption.Amount, &expired)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrPromoCodeInvalid
+	case err != nil:
+		return nil, err
+	case expired:
+		return nil, ErrPromoCodeExpired
+	case uses >= maxUses:
+		return nil, ErrPromoCodeUsedUp
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO promo_redemptions (code, login) VALUES ($1, $2)", code, login)
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation {
+		return nil, ErrPromoCodeRedeemed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE promo_codes SET uses = uses + 1 WHERE code = $1", code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.credit(ctx, tx, login, code, redemption.Amount); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+		login, models.LedgerPromoCode, redemption.Amount, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redemption, tx.Commit()
+}
+
+// checkPromoAttempts records the attempt before counting the failed and
+// unfinished attempts of the user and of the address, so concurrent attempts
+// see each other. It returns the id of the recorded attempt, zero when no
+// limit is configured.
+func (db *DBStore) checkPromoAttempts(ctx context.Context, login string, ip string) (int64, error) {
+	limits := db.cfg.Promo
+	if !limits.IsSet() {
+		return 0, nil
+	}
+
+	var id int64
+
+	row := db.connection.QueryRowContext(ctx,
+		"INSERT INTO promo_attempts (login, ip) VALUES ($1, $2) RETURNING id", login, ip)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	var userFailures, ipFailures int
+
+	row = db.connection.QueryRowContext(ctx,
+		"SELECT COUNT(*) FILTER (WHERE login = $1), COUNT(*) FILTER (WHERE ip = $2) FROM promo_attempts "+
+			"WHERE (login = $1 OR ip = $2) AND id <> $3 AND succeeded IS NOT TRUE "+
+			"AND created_at > now() - $4 * interval '1 second'",
+		login, ip, id, limits.Window.Seconds())
+
+	if err := row.Scan(&userFailures, &ipFailures); err != nil {
+		return 0, err
+	}
+
+	if (limits.MaxFailures > 0 && userFailures >= limits.MaxFailures) ||
+		(limits.MaxIPFailures > 0 && ipFailures >= limits.MaxIPFailures) {
+		db.closePromoAttempt(ctx, id, false)
+
+		return 0, ErrPromoTooManyAttempts
+	}
+
+	return id, nil
+}
+
+// closePromoAttempt records the outcome of the attempt, failed ones keep
+// counting against the limits.
+func (db *DBStore) closePromoAttempt(ctx context.Context, id int64, succeeded bool) {
+	if id == 0 {
+		return
+	}
+
+	_, err := db.connection.ExecContext(ctx,
+		"UPDATE promo_attempts SET succeeded = $1 WHERE id = $2", succeeded, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Couldn't close promo attempt %d", id)
+	}
+}
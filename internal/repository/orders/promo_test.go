@@ -0,0 +1,76 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedeemPromoCodeAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		userFailures int64
+		ipFailures   int64
+		wantErr      error
+		wantClosed   [][]driver.Value
+	}{
+		{
+			name:       "Unknown code",
+			wantErr:    ErrPromoCodeInvalid,
+			wantClosed: [][]driver.Value{{false, int64(7)}},
+		},
+		{
+			name:         "Too many failures of the user",
+			userFailures: 5,
+			wantErr:      ErrPromoTooManyAttempts,
+			wantClosed:   [][]driver.Value{{false, int64(7)}},
+		},
+		{
+			name:       "Too many failures from the address",
+			ipFailures: 20,
+			wantErr:    ErrPromoTooManyAttempts,
+			wantClosed: [][]driver.Value{{false, int64(7)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{results: []fakeResult{
+				{match: "INSERT INTO promo_attempts", columns: []string{"id"}, rows: [][]driver.Value{{int64(7)}}},
+				{
+					match:   "FROM promo_attempts",
+					columns: []string{"login", "ip"},
+					rows:    [][]driver.Value{{tt.userFailures, tt.ipFailures}},
+				},
+			}}
+
+			store := NewDBStore(sql.OpenDB(fake), Config{
+				Promo: PromoAttempts{MaxFailures: 5, MaxIPFailures: 20, Window: time.Hour},
+			})
+			defer store.Close()
+
+			_, err := store.RedeemPromoCode(context.Background(), "test", "192.0.2.1", "AAAAAAAAAAAAAAAA")
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, tt.wantClosed, fake.execArgs("UPDATE promo_attempts"))
+			assert.Empty(t, fake.execArgs("INSERT INTO promo_redemptions"))
+		})
+	}
+}
+
+func TestRedeemPromoCodeWithoutLimits(t *testing.T) {
+	fake := &fakeDB{}
+
+	store := NewDBStore(sql.OpenDB(fake), Config{})
+	defer store.Close()
+
+	_, err := store.RedeemPromoCode(context.Background(), "test", "192.0.2.1", "AAAAAAAAAAAAAAAA")
+	require.ErrorIs(t, err, ErrPromoCodeInvalid)
+
+	assert.Empty(t, fake.execArgs("promo_attempts"))
+}
@@ -0,0 +1,121 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+const reconcileSource = "reconcile"
+
+// Reconcile recomputes every user's balance from orders and ledger and
+// compares it with the points left in accrual lots. With repair the lots
+// are adjusted to the recomputed balance within the same transaction.
+func (db *DBStore) Reconcile(ctx context.Context, repair bool) ([]models.Discrepancy, error) {
+	tx, err := db.connection.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx)
+
+	discrepancies := make([]models.Discrepancy, 0)
+
+	discrepancyRows, err := tx.QueryContext(ctx, `
+		WITH expected AS (
+			SELECT login, SUM(amount) AS amount FROM (
+				SELECT login, accrual AS amount FROM orders
+				WHERE status = 'PROCESSED' AND withdraw IS NULL AND accrual IS NOT NULL
+				UNION ALL
+				SELECT login, -withdraw FROM orders WHERE withdraw IS NOT NULL
+				UNION ALL
+				SELECT login, amount FROM ledger
+			) movements GROUP BY login
+		), materialized AS (
+			SELECT login, SUM(remaining) AS amount FROM accrual_lots GROUP BY login
+		)
+		SELECT u.login, COALESCE(e.amount, 0), COALESCE(m.amount, 0)
+		FROM users u
+		LEFT JOIN expected e ON e.login = u.login
+		LEFT JOIN materialized m ON m.login = u.login
+		WHERE COALESCE(e.amount, 0) <> COALESCE(m.amount, 0)
+		ORDER BY u.login`)
+
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(discrepancyRows)
+
+	for discrepancyRows.Next() {
+		var d models.Discrepancy
+		err = discrepancyRows.Scan(&d.Login, &d.Expected, &d.Materialized)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Difference = d.Expected.Sub(d.Materialized)
+		discrepancies = append(discrepancies, d)
+	}
+
+	err = discrepancyRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if !repair {
+		return discrepancies, nil
+	}
+
+	for _, d := range discrepancies {
+		if err := db.repairLots(ctx, tx, d); err != nil {
+			return nil, err
+		}
+	}
+
+	return discrepancies, tx.Commit()
+}
+
+// repairLots brings the points left in lots to the expected balance: missing
+// points are credited as a new lot, excess is taken from the oldest lots.
+func (db *DBStore) repairLots(ctx context.Context, tx *sql.Tx, d models.Discrepancy) error {
+	if d.Difference.IsPositive() {
+		return db.credit(ctx, tx, d.Login, reconcileSource, d.Difference)
+	}
+
+	lotsRows, err := tx.QueryContext(ctx,
+		"SELECT id, remaining FROM accrual_lots WHERE login = $1 AND remaining > 0 "+
+			"ORDER BY accrued_at, id FOR UPDATE", d.Login)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(lotsRows)
+
+	lots := make([]lot, 0)
+	for lotsRows.Next() {
+		var l lot
+		err = lotsRows.Scan(&l.id, &l.remaining)
+		if err != nil {
+			return err
+		}
+
+		lots = append(lots, l)
+	}
+
+	err = lotsRows.Err()
+	if err != nil {
+		return err
+	}
+
+	return consumeLots(ctx, tx, lots, d.Difference.Neg())
+}
@@ -0,0 +1,67 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reconcileDB() *fakeDB {
+	return &fakeDB{results: []fakeResult{
+		{
+			match:   "WITH expected AS",
+			columns: []string{"login", "expected", "materialized"},
+			rows: [][]driver.Value{
+				{"drained", "100", "250"},
+				{"missing", "300", "200"},
+			},
+		},
+		{
+			match:   "SELECT id, remaining FROM accrual_lots",
+			columns: []string{"id", "remaining"},
+			rows:    [][]driver.Value{{int64(1), "100"}, {int64(2), "150"}},
+		},
+	}}
+}
+
+func TestReconcileReportsDrift(t *testing.T) {
+	fake := reconcileDB()
+
+	store := NewDBStore(sql.OpenDB(fake), Config{})
+	defer store.Close()
+
+	discrepancies, err := store.Reconcile(context.Background(), false)
+	require.NoError(t, err)
+	require.Len(t, discrepancies, 2)
+
+	assert.Equal(t, "drained", discrepancies[0].Login)
+	assert.True(t, decimal.NewFromInt(-150).Equal(discrepancies[0].Difference))
+	assert.Equal(t, "missing", discrepancies[1].Login)
+	assert.True(t, decimal.NewFromInt(100).Equal(discrepancies[1].Difference))
+
+	assert.Empty(t, fake.execArgs("accrual_lots"))
+}
+
+func TestReconcileRepairsBothWays(t *testing.T) {
+	fake := reconcileDB()
+
+	store := NewDBStore(sql.OpenDB(fake), Config{PointsTTL: 24 * time.Hour})
+	defer store.Close()
+
+	_, err := store.Reconcile(context.Background(), true)
+	require.NoError(t, err)
+
+	// the excess is taken from the oldest lots first
+	assert.Equal(t, [][]driver.Value{{"100", int64(1)}, {"50", int64(2)}},
+		fake.execArgs("UPDATE accrual_lots SET remaining"))
+
+	// the missing points are credited as a new lot which expires like others
+	assert.Equal(t, [][]driver.Value{{"missing", reconcileSource, "100", (24 * time.Hour).Seconds(), nil}},
+		fake.execArgs("INSERT INTO accrual_lots"))
+}
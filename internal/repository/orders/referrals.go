@@ -0,0 +1,114 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ReferralBonus is paid to both sides once the invited user gets the first
+// order processed, zero value pays nothing.
+type ReferralBonus struct {
+	Referrer decimal.Decimal
+	Referee  decimal.Decimal
+}
+
+// payReferral rewards the pending referral of the user, the bonuses mature
+// together with the accrual which triggered them.
+func (db *DBStore) payReferral(ctx context.Context, tx *sql.Tx, login string, maturation time.Duration) error {
+	var (
+		id       int64
+		referrer string
+	)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT id, referrer FROM referrals WHERE referee = $1 AND status = $2 FOR UPDATE",
+		login, models.ReferralPending)
+
+	err := row.Scan(&id, &referrer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reference := strconv.FormatInt(id, 10)
+	bonuses := map[string]decimal.Decimal{
+		referrer: db.cfg.Referral.Referrer,
+		login:    db.cfg.Referral.Referee,
+	}
+
+	for _, recipient := range []string{referrer, login} {
+		bonus := bonuses[recipient]
+		if !bonus.IsPositive() {
+			continue
+		}
+
+		if err := db.creditMaturing(ctx, tx, recipient, reference, bonus, maturation); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+			recipient, models.LedgerReferralBonus, bonus, reference)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE referrals SET status = $1, rewarded_at = now() WHERE id = $2", models.ReferralRewarded, id)
+
+	return err
+}
+
+func (db *DBStore) GetReferrals(ctx context.Context, login string) (*models.Referrals, error) {
+	referrals := models.Referrals{Referrals: make([]models.Referral, 0)}
+
+	row := db.connection.QueryRowContext(ctx,
+		"SELECT COALESCE(referral_code, '') FROM users WHERE login = $1", login)
+
+	err := row.Scan(&referrals.Code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	referralsRows, err := db.connection.QueryContext(ctx,
+		"SELECT referee, status, created_at, rewarded_at FROM referrals WHERE referrer = $1 ORDER BY id", login)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(referralsRows)
+
+	for referralsRows.Next() {
+		var referral models.Referral
+		err = referralsRows.Scan(&referral.Login, &referral.Status, &referral.CreatedAt, &referral.RewardedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		referrals.Referrals = append(referrals.Referrals, referral)
+	}
+
+	err = referralsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return &referrals, nil
+}
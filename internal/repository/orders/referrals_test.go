@@ -0,0 +1,40 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayReferralMaturesWithAccrual(t *testing.T) {
+	fake := &fakeDB{results: []fakeResult{
+		{
+			match:   "FROM referrals",
+			columns: []string{"id", "referrer"},
+			rows:    [][]driver.Value{{int64(4), "referrer"}},
+		},
+	}}
+
+	store := NewDBStore(sql.OpenDB(fake), Config{
+		Referral: ReferralBonus{Referrer: decimal.NewFromInt(100), Referee: decimal.NewFromInt(50)},
+	})
+	defer store.Close()
+
+	tx, err := store.connection.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer rollback(tx)
+
+	require.NoError(t, store.payReferral(context.Background(), tx, "referee", 336*time.Hour))
+
+	lots := fake.execArgs("INSERT INTO accrual_lots")
+	require.Len(t, lots, 2)
+	for _, args := range lots {
+		assert.Equal(t, (336 * time.Hour).Seconds(), args[4])
+	}
+}
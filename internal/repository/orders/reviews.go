@@ -0,0 +1,128 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrReviewNotFound = errors.New("order is not held for review")
+
+// GetReviewQueue returns the accruals held for review, the oldest first.
+func (db *DBStore) GetReviewQueue(ctx context.Context) ([]models.HeldAccrual, error) {
+	queue := make([]models.HeldAccrual, 0)
+
+	rows, err := db.connection.QueryContext(ctx,
+		"SELECT number, login, COALESCE(partner, ''), accrual, COALESCE(review_reason, ''), uploaded_at "+
+			"FROM orders WHERE status = $1 ORDER BY uploaded_at", models.OrderReview)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(rows)
+
+	for rows.Next() {
+		var held models.HeldAccrual
+		err = rows.Scan(&held.Number, &held.Login, &held.Partner, &held.Accrual, &held.Reason, &held.UploadedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		queue = append(queue, held)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// DecideReview processes the held accrual when approved, the rejected order
+// becomes INVALID and credits nothing.
+func (db *DBStore) DecideReview(ctx context.Context, number string, approve bool, reviewer string) error {
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var login, status, partner string
+	var accrual *decimal.Decimal
+	row := tx.QueryRowContext(ctx,
+		"SELECT login, status, COALESCE(partner, ''), accrual FROM orders WHERE number = $1 FOR UPDATE", number)
+
+	err = row.Scan(&login, &status, &partner, &accrual)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if status != models.OrderReview {
+		return ErrReviewNotFound
+	}
+
+	newStatus, action := models.OrderInvalid, models.AuditReviewRejected
+	if approve {
+		newStatus, action = models.OrderProcessed, models.AuditReviewApproved
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE orders SET status = $1, "+
+			"accrual = CASE WHEN $1 = 'PROCESSED' THEN accrual END, "+
+			"processed_at = CASE WHEN $1 = 'PROCESSED' THEN now() END "+
+			"WHERE number = $2",
+		newStatus, number)
+	if err != nil {
+		return err
+	}
+
+	err = recordStatusChange(ctx, tx, number, &models.StatusChange{
+		OldStatus: status,
+		NewStatus: newStatus,
+		Accrual:   accrual,
+		Source:    models.HistorySourceReview,
+		Note:      fmt.Sprintf("reviewed by %s", reviewer),
+	})
+	if err != nil {
+		return err
+	}
+
+	if approve {
+		if err := db.completeOrder(ctx, tx, login, number, partner, accrual); err != nil {
+			return err
+		}
+	}
+
+	err = recordAudit(ctx, tx, &models.AuditEvent{
+		Login:   login,
+		Actor:   reviewer,
+		Action:  action,
+		Details: fmt.Sprintf("order %s accrual %s", number, formatAccrual(accrual)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func formatAccrual(accrual *decimal.Decimal) string {
+	if accrual == nil {
+		return "0"
+	}
+
+	return accrual.String()
+}
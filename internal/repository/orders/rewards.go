@@ -0,0 +1,185 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+var (
+	ErrRewardNotFound   = errors.New("reward not found")
+	ErrRewardOutOfStock = errors.New("reward is out of stock")
+)
+
+// GetRewards returns the catalog, inactive rewards are included only when asked.
+func (db *DBStore) GetRewards(ctx context.Context, all bool) ([]models.Reward, error) {
+	rewards := make([]models.Reward, 0)
+
+	rewardsRows, err := db.connection.QueryContext(ctx,
+		"SELECT id, name, kind, COALESCE(description, ''), price, stock, active FROM rewards "+
+			"WHERE active OR $1 ORDER BY id", all)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(rewardsRows)
+
+	for rewardsRows.Next() {
+		var r models.Reward
+		err = rewardsRows.Scan(&r.ID, &r.Name, &r.Kind, &r.Description, &r.Price, &r.Stock, &r.Active)
+		if err != nil {
+			return nil, err
+		}
+
+		rewards = append(rewards, r)
+	}
+
+	err = rewardsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return rewards, nil
+}
+
+func (db *DBStore) CreateReward(ctx context.Context, reward *models.Reward) error {
+	row := db.connection.QueryRowContext(ctx,
+		"INSERT INTO rewards (name, kind, description, price, stock, active) "+
+			"VALUES ($1, $2, $3, $4, $5, COALESCE($6, TRUE)) RETURNING id, active",
+		reward.Name, reward.Kind, reward.Description, reward.Price, reward.Stock, reward.Active)
+
+	return row.Scan(&reward.ID, &reward.Active)
+}
+
+func (db *DBStore) UpdateReward(ctx context.Context, reward *models.Reward) error {
+	row := db.connection.QueryRowContext(ctx,
+		"UPDATE rewards SET name = $1, kind = $2, description = $3, price = $4, stock = $5, "+
+			"active = COALESCE($6, active) WHERE id = $7 RETURNING active",
+		reward.Name, reward.Kind, reward.Description, reward.Price, reward.Stock, reward.Active, reward.ID)
+
+	err := row.Scan(&reward.Active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRewardNotFound
+	}
+
+	return err
+}
+
+// Redeem exchanges points for the reward, the points are debited and the stock
+// is taken within one transaction.
+func (db *DBStore) Redeem(ctx context.Context, login string, rewardID int64) (*models.Redemption, error) {
+	var stock *int
+
+	redemption := models.Redemption{RewardID: rewardID}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT name, price, stock FROM rewards WHERE id = $1 AND active FOR UPDATE", rewardID)
+
+	err = row.Scan(&redemption.Reward, &redemption.Price, &stock)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRewardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stock != nil && *stock <= 0 {
+		return nil, ErrRewardOutOfStock
+	}
+
+	if err := db.cfg.Amount.Validate(redemption.Price); err != nil {
+		return nil, err
+	}
+
+	if err := db.debit(ctx, tx, login, redemption.Price); err != nil {
+		return nil, err
+	}
+
+	if err := db.checkDailyAmount(ctx, tx, login, redemption.Price); err != nil {
+		return nil, err
+	}
+
+	if err := db.checkWithdrawalLimits(ctx, tx, login, redemption.Price); err != nil {
+		return nil, db.auditLimitBreach(ctx, login, err)
+	}
+
+	if stock != nil {
+		_, err = tx.ExecContext(ctx, "UPDATE rewards SET stock = stock - 1 WHERE id = $1", rewardID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	redemption.Code, err = models.NewRedemptionCode()
+	if err != nil {
+		return nil, err
+	}
+
+	row = tx.QueryRowContext(ctx,
+		"INSERT INTO redemptions (login, reward_id, price, code) VALUES ($1, $2, $3, $4) "+
+			"RETURNING id, created_at",
+		login, rewardID, redemption.Price, redemption.Code)
+
+	err = row.Scan(&redemption.ID, &redemption.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO ledger (login, kind, amount, reference, created_at) VALUES ($1, $2, $3, $4, $5)",
+		login, models.LedgerRedemption, redemption.Price.Neg(), strconv.FormatInt(redemption.ID, 10),
+		redemption.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redemption, tx.Commit()
+}
+
+func (db *DBStore) GetRedemptions(ctx context.Context, login string) ([]models.Redemption, error) {
+	redemptions := make([]models.Redemption, 0)
+
+	redemptionsRows, err := db.connection.QueryContext(ctx,
+		"SELECT r.id, r.reward_id, w.name, r.price, r.code, r.created_at "+
+			"FROM redemptions r JOIN rewards w ON w.id = r.reward_id WHERE r.login = $1 ORDER BY r.id", login)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(redemptionsRows)
+
+	for redemptionsRows.Next() {
+		var r models.Redemption
+		err = redemptionsRows.Scan(&r.ID, &r.RewardID, &r.Reward, &r.Price, &r.Code, &r.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		redemptions = append(redemptions, r)
+	}
+
+	err = redemptionsRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return redemptions, nil
+}
@@ -0,0 +1,96 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+)
+
+// GetStatement merges accruals, withdrawals and ledger entries into one
+// chronological feed with running balance. Next page cursor is nil
+// for the last page.
+func (db *DBStore) GetStatement(ctx context.Context, login string,
+	page models.Page) ([]models.StatementLine, *models.Cursor, error) {
+	var afterAt, afterSource, afterKey interface{}
+	if page.After != nil {
+		afterAt, afterSource, afterKey = page.After.At.UTC(), page.After.Source, page.After.Key
+	}
+
+	lines := make([]models.StatementLine, 0, page.Limit)
+	cursors := make([]models.Cursor, 0, page.Limit)
+
+	statementRows, err := db.connection.QueryContext(ctx, `
+		WITH entries AS (
+			SELECT processed_at AS at, 1 AS source, number::text AS key,
+				$2::text AS kind, accrual AS amount, number::text AS reference
+			FROM orders
+			WHERE login = $1 AND status = 'PROCESSED' AND withdraw IS NULL AND accrual IS NOT NULL
+			UNION ALL
+			SELECT uploaded_at, 1, number::text, $3::text, -withdraw, number::text
+			FROM orders
+			WHERE login = $1 AND withdraw IS NOT NULL
+			UNION ALL
+			SELECT created_at, 2, id::text, kind, amount, COALESCE(reference, '')
+			FROM ledger
+			WHERE login = $1
+		), statement AS (
+			SELECT *, SUM(amount) OVER (ORDER BY at, source, key) AS balance FROM entries
+		)
+		SELECT at, source, key, kind, amount, reference, balance FROM statement
+		WHERE ($4::timestamp IS NULL OR at >= $4::timestamp)
+			AND ($5::timestamp IS NULL OR at < $5::timestamp)
+			AND ($6::timestamp IS NULL OR (at, source, key) > ($6::timestamp, $7::integer, $8::text))
+		ORDER BY at, source, key
+		LIMIT $9`,
+		login, models.LedgerAccrual, models.LedgerWithdrawal,
+		nullTime(page.From), nullTime(page.To), afterAt, afterSource, afterKey, page.Limit+1)
+
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(statementRows)
+
+	for statementRows.Next() {
+		var (
+			line   models.StatementLine
+			cursor models.Cursor
+		)
+		err = statementRows.Scan(&cursor.At, &cursor.Source, &cursor.Key,
+			&line.Kind, &line.Amount, &line.Reference, &line.Balance)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line.CreatedAt = cursor.At
+		lines = append(lines, line)
+		cursors = append(cursors, cursor)
+	}
+
+	err = statementRows.Err()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) <= page.Limit {
+		return lines, nil, nil
+	}
+
+	return lines[:page.Limit], &cursors[page.Limit-1], nil
+}
+
+// nullTime converts zero time to NULL for optional query filters.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t.UTC()
+}
@@ -0,0 +1,209 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var ErrTiersDisabled = errors.New("membership tiers are not configured")
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// TierConfig describes membership tiers, no tiers means accruals aren't multiplied.
+type TierConfig struct {
+	Tiers models.Tiers
+	// Window is the rolling period of accruals counted for the tier, zero counts all of them.
+	Window time.Duration
+	// Inactivity is the period without accruals after which the tier is lowered, zero never lowers it.
+	Inactivity time.Duration
+}
+
+// accrue credits the order accrual boosted by the active campaigns and
+// multiplied by the user tier, then re-evaluates the tier with the accrual counted.
+// The credited points become spendable after the maturation period.
+func (db *DBStore) accrue(ctx context.Context, tx *sql.Tx, login string, number string, accrual decimal.Decimal,
+	maturation time.Duration) error {
+	if err := db.creditMaturing(ctx, tx, login, number, accrual, maturation); err != nil {
+		return err
+	}
+
+	if err := db.applyCampaigns(ctx, tx, login, number, accrual, maturation); err != nil {
+		return err
+	}
+
+	tiers := db.cfg.Tier.Tiers
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	current, err := db.currentTier(ctx, tx, login)
+	if err != nil {
+		return err
+	}
+
+	bonus := db.truncatePoints(accrual.Mul(tiers[current].Multiplier).Sub(accrual))
+
+	if bonus.IsPositive() {
+		if err := db.creditMaturing(ctx, tx, login, number, bonus, maturation); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4)",
+			login, models.LedgerTierBonus, bonus, number)
+		if err != nil {
+			return err
+		}
+	}
+
+	accrued, err := db.tierAccrued(ctx, tx, login)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_tiers (login, tier) VALUES ($1, $2)
+		ON CONFLICT (login) DO UPDATE SET tier = EXCLUDED.tier, evaluated_at = now(), last_activity_at = now()`,
+		login, tiers[tiers.Reached(accrued)].Name)
+
+	return err
+}
+
+func (db *DBStore) currentTier(ctx context.Context, q queryRower, login string) (int, error) {
+	var name string
+
+	row := q.QueryRowContext(ctx, "SELECT tier FROM user_tiers WHERE login = $1", login)
+
+	err := row.Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return db.cfg.Tier.Tiers.Index(name), nil
+}
+
+// tierAccrued sums the processed accruals within the tier window.
+func (db *DBStore) tierAccrued(ctx context.Context, q queryRower, login string) (decimal.Decimal, error) {
+	var accrued decimal.Decimal
+
+	row := q.QueryRowContext(ctx,
+		"SELECT COALESCE(SUM(accrual), 0) FROM orders WHERE login = $1 AND status = 'PROCESSED' "+
+			"AND ($2::double precision IS NULL OR processed_at > now() - $2 * interval '1 second')",
+		login, intervalSeconds(db.cfg.Tier.Window))
+
+	err := row.Scan(&accrued)
+
+	return accrued, err
+}
+
+// GetTier returns the user tier and the progress towards the next one.
+func (db *DBStore) GetTier(ctx context.Context, login string) (*models.TierStatus, error) {
+	tiers := db.cfg.Tier.Tiers
+	if len(tiers) == 0 {
+		return nil, ErrTiersDisabled
+	}
+
+	current, err := db.currentTier(ctx, db.connection, login)
+	if err != nil {
+		return nil, err
+	}
+
+	accrued, err := db.tierAccrued(ctx, db.connection, login)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.TierStatus{
+		Tier:       tiers[current].Name,
+		Multiplier: tiers[current].Multiplier,
+		Accrued:    accrued,
+	}
+
+	if current+1 < len(tiers) {
+		next := tiers[current+1]
+		remaining := decimal.Max(next.Threshold.Sub(accrued), decimal.Zero)
+
+		status.NextTier = next.Name
+		status.NextThreshold = &next.Threshold
+		status.Remaining = &remaining
+	}
+
+	return &status, nil
+}
+
+// DowngradeTiers lowers by one level the tier of the users without accruals
+// for the inactivity period. It returns the number of downgraded users.
+func (db *DBStore) DowngradeTiers(ctx context.Context) (int64, error) {
+	tiers := db.cfg.Tier.Tiers
+	if len(tiers) == 0 || db.cfg.Tier.Inactivity <= 0 {
+		return 0, nil
+	}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(tx)
+
+	inactive := make(map[string]string)
+
+	tiersRows, err := tx.QueryContext(ctx,
+		"SELECT login, tier FROM user_tiers "+
+			"WHERE last_activity_at <= now() - $1 * interval '1 second' "+
+			"AND evaluated_at <= now() - $1 * interval '1 second' FOR UPDATE",
+		db.cfg.Tier.Inactivity.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Error().Err(err).Msgf("Couldn't close rows")
+		}
+	}(tiersRows)
+
+	for tiersRows.Next() {
+		var login, tier string
+		err = tiersRows.Scan(&login, &tier)
+		if err != nil {
+			return 0, err
+		}
+
+		inactive[login] = tier
+	}
+
+	err = tiersRows.Err()
+	if err != nil {
+		return 0, err
+	}
+
+	var downgraded int64
+	for login, tier := range inactive {
+		current := tiers.Index(tier)
+		if current == 0 {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE user_tiers SET tier = $1, evaluated_at = now() WHERE login = $2",
+			tiers[current-1].Name, login)
+		if err != nil {
+			return 0, err
+		}
+
+		downgraded++
+	}
+
+	return downgraded, tx.Commit()
+}
@@ -0,0 +1,101 @@
+package orders
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrSelfTransfer          = errors.New("can't transfer points to yourself")
+	ErrRecipientNotFound     = errors.New("recipient not found")
+	ErrTransferBelowMinimum  = errors.New("transfer sum is below minimum")
+	ErrTransferAboveMaximum  = errors.New("transfer sum is above maximum")
+	ErrTransferLimitExceeded = errors.New("daily transfer limit exceeded")
+)
+
+// TransferLimits restricts peer-to-peer transfers, zero value means no limit.
+type TransferLimits struct {
+	Min      decimal.Decimal
+	Max      decimal.Decimal
+	DailyMax decimal.Decimal
+}
+
+// Transfer moves points from the user to the recipient within one transaction.
+func (db *DBStore) Transfer(ctx context.Context, login string, transfer *models.Transfer) error {
+	if login == transfer.Login {
+		return ErrSelfTransfer
+	}
+
+	if err := db.cfg.Amount.Validate(transfer.Sum); err != nil {
+		return err
+	}
+
+	limits := db.cfg.Transfer
+	if transfer.Sum.LessThan(limits.Min) {
+		return ErrTransferBelowMinimum
+	}
+	if limits.Max.IsPositive() && transfer.Sum.GreaterThan(limits.Max) {
+		return ErrTransferAboveMaximum
+	}
+
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	recipient := transfer.Login
+	err = userExists(ctx, tx, recipient)
+	if errors.Is(err, ErrUserNotFound) {
+		return ErrRecipientNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := db.debit(ctx, tx, login, transfer.Sum); err != nil {
+		return err
+	}
+
+	// Lots are locked by debit already, so concurrent transfers of the user
+	// can't slip past the daily limit.
+	if limits.DailyMax.IsPositive() {
+		var transferred decimal.Decimal
+		row := tx.QueryRowContext(ctx,
+			"SELECT COALESCE(-SUM(amount), 0) FROM ledger "+
+				"WHERE login = $1 AND kind = $2 AND created_at >= date_trunc('day', now())",
+			login, models.LedgerTransferOut)
+		if err := row.Scan(&transferred); err != nil {
+			return err
+		}
+
+		if transferred.Add(transfer.Sum).GreaterThan(limits.DailyMax) {
+			return ErrTransferLimitExceeded
+		}
+	}
+
+	if err := db.checkDailyAmount(ctx, tx, login, transfer.Sum); err != nil {
+		return err
+	}
+
+	if err := db.checkWithdrawalLimits(ctx, tx, login, transfer.Sum); err != nil {
+		return db.auditLimitBreach(ctx, login, err)
+	}
+
+	if err := db.credit(ctx, tx, recipient, login, transfer.Sum); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO ledger (login, kind, amount, reference) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)",
+		login, models.LedgerTransferOut, transfer.Sum.Neg(), recipient,
+		recipient, models.LedgerTransferIn, transfer.Sum, login)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/go-rfe/logging/log"
 	"github.com/jackc/pgconn"
 	_ "github.com/jackc/pgx/v4/stdlib" // init postgresql driver
 
@@ -17,28 +18,107 @@ const (
 
 type DBStore struct {
 	connection *sql.DB
+	cfg        Config
 }
 
-func NewDBStore(connection *sql.DB) *DBStore {
+func NewDBStore(connection *sql.DB, cfg Config) *DBStore {
 	db := DBStore{
 		connection: connection,
+		cfg:        cfg,
 	}
 
 	return &db
 }
 
-func (db *DBStore) CreateUser(ctx context.Context, login string, password string) error {
+// CreateUser stores the user with a new referral code of their own and
+// tracks the referral when the code of the inviting user is given. The ip
+// is the client address of the registration.
+func (db *DBStore) CreateUser(ctx context.Context, login string, password string, referralCode string,
+	ip string) error {
 	var pgErr *pgconn.PgError
 
-	_, err := db.connection.ExecContext(ctx,
-		"INSERT INTO users (login, password) VALUES ($1, $2)",
-		login, password)
+	tx, err := db.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx)
+
+	var referrer string
+	if referralCode != "" {
+		referrer, err = db.lockReferrer(ctx, tx, referralCode, ip)
+		if err != nil {
+			return err
+		}
+	}
+
+	code, err := models.NewReferralCode()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO users (login, password, referral_code, registration_ip) VALUES ($1, $2, $3, NULLIF($4, ''))",
+		login, password, code, ip)
 
 	if err != nil && errors.As(err, &pgErr) && pgErr.Code == pgErrCodeUniqueViolation {
 		return ErrUserExists
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	if referrer != "" {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO referrals (referrer, referee, status) VALUES ($1, $2, $3)",
+			referrer, login, models.ReferralPending)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lockReferrer finds the owner of the referral code and checks the anti-abuse rules.
+// The registration from an address the referrer registered or uploaded orders
+// from is taken for the referrer inviting themselves.
+func (db *DBStore) lockReferrer(ctx context.Context, tx *sql.Tx, referralCode string, ip string) (string, error) {
+	var (
+		referrer string
+		sameIP   bool
+	)
+
+	row := tx.QueryRowContext(ctx,
+		"SELECT login, $2 <> '' AND (COALESCE(registration_ip = $2, FALSE) OR EXISTS "+
+			"(SELECT 1 FROM upload_attempts WHERE login = users.login AND ip = $2)) "+
+			"FROM users WHERE referral_code = $1 FOR UPDATE", referralCode, ip)
+
+	err := row.Scan(&referrer, &sameIP)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrInvalidReferralCode
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if sameIP {
+		return "", ErrSelfReferral
+	}
+
+	if db.cfg.ReferralLimit > 0 {
+		var invited int
+
+		row = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM referrals WHERE referrer = $1", referrer)
+		if err := row.Scan(&invited); err != nil {
+			return "", err
+		}
+
+		if invited >= db.cfg.ReferralLimit {
+			return "", ErrReferralLimitExceeded
+		}
+	}
+
+	return referrer, nil
 }
 
 func (db *DBStore) GetUser(ctx context.Context, login string) (*models.User, error) {
@@ -66,3 +146,10 @@ func (db *DBStore) GetUser(ctx context.Context, login string) (*models.User, err
 func (db *DBStore) Close() error {
 	return db.connection.Close()
 }
+
+func rollback(tx *sql.Tx) {
+	err := tx.Rollback()
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Error().Err(err).Msgf("Couldn't rollback transaction")
+	}
+}
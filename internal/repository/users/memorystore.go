@@ -8,19 +8,28 @@ import (
 )
 
 type InMemoryStore struct {
-	usersCache map[string]*models.User
-	mu         sync.RWMutex
+	usersCache    map[string]*models.User
+	referralCodes map[string]string
+	referrals     map[string][]string
+	ips           map[string]string
+	cfg           Config
+	mu            sync.RWMutex
 }
 
-func NewInMemoryStore() *InMemoryStore {
+func NewInMemoryStore(cfg Config) *InMemoryStore {
 	m := InMemoryStore{
-		usersCache: make(map[string]*models.User),
+		usersCache:    make(map[string]*models.User),
+		referralCodes: make(map[string]string),
+		referrals:     make(map[string][]string),
+		ips:           make(map[string]string),
+		cfg:           cfg,
 	}
 
 	return &m
 }
 
-func (m *InMemoryStore) CreateUser(_ context.Context, login string, password string) error {
+func (m *InMemoryStore) CreateUser(_ context.Context, login string, password string, referralCode string,
+	ip string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -28,12 +37,39 @@ func (m *InMemoryStore) CreateUser(_ context.Context, login string, password str
 		return ErrUserExists
 	}
 
+	var referrer string
+	if referralCode != "" {
+		var ok bool
+		if referrer, ok = m.referralCodes[referralCode]; !ok {
+			return ErrInvalidReferralCode
+		}
+
+		if ip != "" && m.ips[referrer] == ip {
+			return ErrSelfReferral
+		}
+
+		if m.cfg.ReferralLimit > 0 && len(m.referrals[referrer]) >= m.cfg.ReferralLimit {
+			return ErrReferralLimitExceeded
+		}
+	}
+
+	code, err := models.NewReferralCode()
+	if err != nil {
+		return err
+	}
+
 	user := &models.User{
 		Login:    login,
 		Password: password,
 	}
 
 	m.usersCache[login] = user
+	m.referralCodes[code] = login
+	m.ips[login] = ip
+
+	if referrer != "" {
+		m.referrals[referrer] = append(m.referrals[referrer], login)
+	}
 
 	return nil
 }
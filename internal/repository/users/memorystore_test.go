@@ -0,0 +1,51 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserSelfReferral(t *testing.T) {
+	store := NewInMemoryStore(Config{})
+	ctx := context.Background()
+
+	require.NoError(t, store.CreateUser(ctx, "referrer", "secret", "", "192.0.2.1"))
+
+	var code string
+	for c, login := range store.referralCodes {
+		if login == "referrer" {
+			code = c
+		}
+	}
+	require.NotEmpty(t, code)
+
+	tests := []struct {
+		name    string
+		login   string
+		ip      string
+		wantErr error
+	}{
+		{name: "Same address as referrer", login: "alias", ip: "192.0.2.1", wantErr: ErrSelfReferral},
+		{name: "Other address", login: "friend", ip: "198.51.100.7"},
+		{name: "Unknown address", login: "colleague"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.CreateUser(ctx, tt.login, "secret", code, tt.ip)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			_, err = store.GetUser(ctx, tt.login)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, ErrUserNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, []string{"friend", "colleague"}, store.referrals["referrer"])
+}
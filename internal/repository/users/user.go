@@ -10,7 +10,18 @@ import (
 var ErrUserExists = errors.New("user already exists")
 var ErrUserNotFound = errors.New("user not found")
 
+var (
+	ErrInvalidReferralCode   = errors.New("referral code is invalid")
+	ErrSelfReferral          = errors.New("can't refer yourself")
+	ErrReferralLimitExceeded = errors.New("referrer has invited too many users")
+)
+
+// Config restricts referrals, zero ReferralLimit means no limit per referrer.
+type Config struct {
+	ReferralLimit int
+}
+
 type Store interface {
-	CreateUser(ctx context.Context, login string, password string) error
+	CreateUser(ctx context.Context, login string, password string, referralCode string, ip string) error
 	GetUser(ctx context.Context, login string) (*models.User, error)
 }
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+const (
+	expiryTimeout = 10 * time.Second
+)
+
+type ExpiryConfig struct {
+	ExpiryInterval time.Duration
+}
+
+type ExpiryWorker struct {
+	Cfg ExpiryConfig
+}
+
+func (ew *ExpiryWorker) Run(ctx context.Context, ordersStore orders.Store) {
+	expiryTicker := time.NewTicker(ew.Cfg.ExpiryInterval)
+	defer expiryTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expiryTicker.C:
+			ExpirePoints(ctx, ordersStore)
+			ReleaseHolds(ctx, ordersStore)
+			DowngradeTiers(ctx, ordersStore)
+			TransferClaimedOrders(ctx, ordersStore)
+			PurgeUploadAttempts(ctx, ordersStore)
+		}
+	}
+}
+
+func ExpirePoints(ctx context.Context, ordersStore orders.Store) {
+	expireContext, expireCancel := context.WithTimeout(ctx, expiryTimeout)
+	defer expireCancel()
+
+	expired, err := ordersStore.ExpirePoints(expireContext)
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't expire points")
+
+		return
+	}
+
+	if expired > 0 {
+		log.Info().Msgf("Expired %d accrual lots", expired)
+	}
+}
+
+func ReleaseHolds(ctx context.Context, ordersStore orders.Store) {
+	releaseContext, releaseCancel := context.WithTimeout(ctx, expiryTimeout)
+	defer releaseCancel()
+
+	released, err := ordersStore.ReleaseExpiredHolds(releaseContext)
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't release expired withdrawal holds")
+
+		return
+	}
+
+	if released > 0 {
+		log.Info().Msgf("Released %d expired withdrawal holds", released)
+	}
+}
+
+func DowngradeTiers(ctx context.Context, ordersStore orders.Store) {
+	downgradeContext, downgradeCancel := context.WithTimeout(ctx, expiryTimeout)
+	defer downgradeCancel()
+
+	downgraded, err := ordersStore.DowngradeTiers(downgradeContext)
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't downgrade inactive members")
+
+		return
+	}
+
+	if downgraded > 0 {
+		log.Info().Msgf("Downgraded tiers of %d inactive members", downgraded)
+	}
+}
+
+func TransferClaimedOrders(ctx context.Context, ordersStore orders.Store) {
+	transferContext, transferCancel := context.WithTimeout(ctx, expiryTimeout)
+	defer transferCancel()
+
+	moved, err := ordersStore.TransferClaimedOrders(transferContext)
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't transfer claimed orders")
+
+		return
+	}
+
+	if moved > 0 {
+		log.Info().Msgf("Moved %d uncontested claimed orders", moved)
+	}
+}
+
+func PurgeUploadAttempts(ctx context.Context, ordersStore orders.Store) {
+	purgeContext, purgeCancel := context.WithTimeout(ctx, expiryTimeout)
+	defer purgeCancel()
+
+	purged, err := ordersStore.PurgeUploadAttempts(purgeContext)
+	if err != nil {
+		log.Error().Err(err).Msg("Couldn't purge order upload attempts")
+
+		return
+	}
+
+	if purged > 0 {
+		log.Info().Msgf("Purged %d order upload attempts", purged)
+	}
+}
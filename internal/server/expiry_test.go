@@ -0,0 +1,64 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-rfe/loyalty-system/internal/server"
+	"github.com/golang/mock/gomock"
+)
+
+func TestExpirePoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		expired int64
+		err     error
+	}{
+		{
+			name:    "Expired lots",
+			expired: 2,
+		},
+		{
+			name: "Store failure",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	_, store := getMocks(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.EXPECT().ExpirePoints(gomock.Any()).Return(tt.expired, tt.err).Times(1)
+			server.ExpirePoints(context.Background(), store)
+		})
+	}
+}
+
+func TestReleaseHolds(t *testing.T) {
+	_, store := getMocks(t)
+
+	store.EXPECT().ReleaseExpiredHolds(gomock.Any()).Return(int64(1), nil).Times(1)
+	server.ReleaseHolds(context.Background(), store)
+}
+
+func TestDowngradeTiers(t *testing.T) {
+	_, store := getMocks(t)
+
+	store.EXPECT().DowngradeTiers(gomock.Any()).Return(int64(3), nil).Times(1)
+	server.DowngradeTiers(context.Background(), store)
+}
+
+func TestTransferClaimedOrders(t *testing.T) {
+	_, store := getMocks(t)
+
+	store.EXPECT().TransferClaimedOrders(gomock.Any()).Return(int64(1), nil).Times(1)
+	server.TransferClaimedOrders(context.Background(), store)
+}
+
+func TestPurgeUploadAttempts(t *testing.T) {
+	_, store := getMocks(t)
+
+	store.EXPECT().PurgeUploadAttempts(gomock.Any()).Return(int64(10), nil).Times(1)
+	server.PurgeUploadAttempts(context.Background(), store)
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+// AdminOnly lets through requests of the users listed as admins.
+func AdminOnly(admins []string) func(next http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(admins))
+	for _, admin := range admins {
+		allowed[admin] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			login, err := getLoginFromRequest(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+
+				return
+			}
+
+			if _, ok := allowed[login]; !ok {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func AdminUsersHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/{login}/balance", getUserBalanceHandler(ordersStore))
+		r.Get("/{login}/adjustments", getUserAdjustmentsHandler(ordersStore))
+		r.Post("/{login}/adjustments", createAdjustmentHandler(ordersStore))
+		r.Get("/{login}/limits", getUserLimitsHandler(ordersStore))
+		r.Put("/{login}/limits", setUserLimitsHandler(ordersStore))
+		r.Get("/{login}/audit", getUserAuditHandler(ordersStore))
+	}
+}
+
+func getUserBalanceHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		writeBalance(requestContext, w, r, chi.URLParam(r, "login"), ordersStore)
+	}
+}
+
+func getUserAdjustmentsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		writeAdjustments(requestContext, w, chi.URLParam(r, "login"), ordersStore)
+	}
+}
+
+func createAdjustmentHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var adjustment models.Adjustment
+		err = json.NewDecoder(r.Body).Decode(&adjustment)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		adjustment.Login = chi.URLParam(r, "login")
+		adjustment.Operator = operator
+
+		if err := adjustment.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.Adjust(requestContext, &adjustment)
+		switch {
+		case errors.Is(err, orders.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case errors.Is(err, orders.ErrInsufficientBalance):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't adjust balance for %s: %q", adjustment.Login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = models.Encode(&adjustment, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func getUserLimitsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login := chi.URLParam(r, "login")
+
+		limits, err := ordersStore.GetWithdrawalLimits(requestContext, login)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get withdrawal limits for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&limits, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func setUserLimitsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var limits []models.WithdrawalLimit
+		err = json.NewDecoder(r.Body).Decode(&limits)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		for i := range limits {
+			if err := limits[i].ValidateFields(); err != nil {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+				return
+			}
+		}
+
+		login := chi.URLParam(r, "login")
+
+		err = ordersStore.SetWithdrawalLimits(requestContext, login, operator, limits)
+		switch {
+		case errors.Is(err, orders.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't set withdrawal limits for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+func getUserAuditHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login := chi.URLParam(r, "login")
+
+		events, err := ordersStore.GetAuditEvents(requestContext, login)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get audit events for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&events, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
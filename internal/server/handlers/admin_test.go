@@ -0,0 +1,192 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wantAdmin struct {
+	code int
+	data string
+}
+
+type testAdmin struct {
+	name       string
+	method     string
+	url        string
+	body       string
+	authHeader string
+	buildStubs func(store *mocks.MockStore)
+	want       wantAdmin
+}
+
+func TestAdminHandlers(t *testing.T) {
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	_, userToken, err := jwtToken.Encode(map[string]interface{}{"sub": "user"})
+	require.NoError(t, err)
+
+	tests := []testAdmin{
+		{
+			name:       "Get user balance as of date",
+			method:     http.MethodGet,
+			url:        "/api/admin/users/user/balance?as_of=2014-11-12T11:45:26.371Z",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "{\"current\":42,\"withdrawn\":8,\"pending\":0,\"locked\":0,\"expiring\":0}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), "user", getDate()).Return(&models.Balance{
+					Current:   decimal.NewFromInt(42),
+					Withdrawn: decimal.NewFromInt(8),
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Credit goodwill points",
+			method:     http.MethodPost,
+			url:        "/api/admin/users/user/adjustments",
+			body:       "{\"amount\":100,\"reason\":\"GOODWILL\",\"note\":\"late delivery\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusCreated,
+				data: "{\"id\":0,\"login\":\"user\",\"amount\":100,\"reason\":\"GOODWILL\"," +
+					"\"note\":\"late delivery\",\"operator\":\"test\",\"created_at\":\"0001-01-01T00:00:00Z\"}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Adjust(gomock.Any(), &models.Adjustment{
+					Login:    "user",
+					Amount:   decimal.NewFromInt(100),
+					Reason:   models.ReasonGoodwill,
+					Note:     "late delivery",
+					Operator: "test",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Adjustment without reason",
+			method:     http.MethodPost,
+			url:        "/api/admin/users/user/adjustments",
+			body:       "{\"amount\":-100,\"note\":\"fraud\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Adjust(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Set user withdrawal limits",
+			method:     http.MethodPut,
+			url:        "/api/admin/users/user/limits",
+			body:       "[{\"period\":\"DAILY\",\"max_count\":3,\"max_amount\":500}]",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().SetWithdrawalLimits(gomock.Any(), "user", "test", []models.WithdrawalLimit{
+					{Period: models.LimitDaily, MaxCount: 3, MaxAmount: decimal.NewFromInt(500)},
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Set withdrawal limit with unknown period",
+			method:     http.MethodPut,
+			url:        "/api/admin/users/user/limits",
+			body:       "[{\"period\":\"YEARLY\",\"max_count\":3}]",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().SetWithdrawalLimits(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Get user audit events",
+			method:     http.MethodGet,
+			url:        "/api/admin/users/user/audit",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "[{\"id\":1,\"login\":\"user\",\"action\":\"WITHDRAWAL_LIMIT_EXCEEDED\"," +
+					"\"details\":\"withdrawal limit exceeded: DAILY count 3\",\"created_at\":\"2014-11-12T11:45:26.371Z\"}]\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetAuditEvents(gomock.Any(), "user").Return([]models.AuditEvent{
+					{
+						ID:        1,
+						Login:     "user",
+						Action:    models.AuditWithdrawalLimitExceeded,
+						Details:   "withdrawal limit exceeded: DAILY count 3",
+						CreatedAt: getDate(),
+					},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Forbidden for regular users",
+			method:     http.MethodGet,
+			url:        "/api/admin/users/test/balance",
+			authHeader: "Bearer " + userToken,
+			want: wantAdmin{
+				code: http.StatusForbidden,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetBalance(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
+
+func testAdminRequest(t *testing.T, ts *httptest.Server, testData testAdmin) {
+	t.Helper()
+
+	req, err := http.NewRequest(testData.method, ts.URL+testData.url, strings.NewReader(testData.body))
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", testData.authHeader)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, testData.want.code, resp.StatusCode)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	if testData.want.data != "" {
+		assert.JSONEq(t, testData.want.data, string(respBody))
+	}
+}
@@ -3,21 +3,28 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-rfe/logging/log"
 	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
-	"github.com/shopspring/decimal"
 )
 
 func BalanceHandler(ordersStore orders.Store) func(r chi.Router) {
 	return func(r chi.Router) {
 		r.Get("/", getBalanceHandler(ordersStore))
 		r.Get("/withdrawals", getWithdrawalsHandler(ordersStore))
+		r.Get("/ledger", getLedgerHandler(ordersStore))
+		r.Get("/adjustments", getAdjustmentsHandler(ordersStore))
+		r.Post("/transfer", transferHandler(ordersStore))
 		r.Post("/withdraw", withdrawHandler(ordersStore))
+		r.Post("/withdraw/authorize", authorizeWithdrawHandler(ordersStore))
+		r.Post("/withdraw/{hold}/capture", holdHandler(ordersStore.CaptureWithdraw))
+		r.Post("/withdraw/{hold}/void", holdHandler(ordersStore.VoidWithdraw))
 	}
 }
 
@@ -37,7 +44,65 @@ func getBalanceHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *
 			return
 		}
 
-		balance, err := getBalance(requestContext, login, ordersStore)
+		writeBalance(requestContext, w, r, login, ordersStore)
+	}
+}
+
+// writeBalance responds with the current balance or with the historical one
+// when as_of query parameter is set.
+func writeBalance(ctx context.Context, w http.ResponseWriter, r *http.Request, login string, ordersStore orders.Store) {
+	var (
+		balance *models.Balance
+		err     error
+	)
+
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		asOfTime, parseErr := time.Parse(time.RFC3339, asOf)
+		if parseErr != nil {
+			http.Error(w, fmt.Sprintf("bad as_of parameter: %q", parseErr), http.StatusBadRequest)
+
+			return
+		}
+
+		balance, err = ordersStore.GetBalanceAsOf(ctx, login, asOfTime)
+	} else {
+		balance, err = ordersStore.GetBalance(ctx, login)
+	}
+
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("couldn't get balance for %s: %q", login, err),
+			http.StatusInternalServerError,
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = models.Encode(balance, w)
+	if err != nil {
+		log.Error().Err(err).Msg("Cannot send request")
+	}
+}
+
+func getWithdrawalsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		withdrawals, err := ordersStore.GetWithdrawals(requestContext, login)
 		if err != nil {
 			http.Error(
 				w,
@@ -49,14 +114,14 @@ func getBalanceHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		err = models.Encode(balance, w)
+		err = models.Encode(withdrawals, w)
 		if err != nil {
 			log.Error().Err(err).Msg("Cannot send request")
 		}
 	}
 }
 
-func getWithdrawalsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+func getLedgerHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
 		defer requestCancel()
@@ -72,11 +137,11 @@ func getWithdrawalsHandler(ordersStore orders.Store) func(w http.ResponseWriter,
 			return
 		}
 
-		withdrawals, err := ordersStore.GetWithdrawals(requestContext, login)
+		ledger, err := ordersStore.GetLedger(requestContext, login)
 		if err != nil {
 			http.Error(
 				w,
-				fmt.Sprintf("couldn't get balance for %s: %q", login, err),
+				fmt.Sprintf("couldn't get ledger for %s: %q", login, err),
 				http.StatusInternalServerError,
 			)
 
@@ -84,13 +149,52 @@ func getWithdrawalsHandler(ordersStore orders.Store) func(w http.ResponseWriter,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		err = models.Encode(withdrawals, w)
+		err = models.Encode(ledger, w)
 		if err != nil {
 			log.Error().Err(err).Msg("Cannot send request")
 		}
 	}
 }
 
+func getAdjustmentsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		writeAdjustments(requestContext, w, login, ordersStore)
+	}
+}
+
+func writeAdjustments(ctx context.Context, w http.ResponseWriter, login string, ordersStore orders.Store) {
+	adjustments, err := ordersStore.GetAdjustments(ctx, login)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("couldn't get adjustments for %s: %q", login, err),
+			http.StatusInternalServerError,
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = models.Encode(adjustments, w)
+	if err != nil {
+		log.Error().Err(err).Msg("Cannot send request")
+	}
+}
+
 func withdrawHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
@@ -121,24 +225,25 @@ func withdrawHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *ht
 			return
 		}
 
-		balance, err := getBalance(requestContext, login, ordersStore)
-		if err != nil {
-			http.Error(
-				w,
-				fmt.Sprintf("couldn't get balance for %s: %q", login, err),
-				http.StatusInternalServerError,
-			)
+		err = ordersStore.Withdraw(requestContext, login, &withdraw)
+		if errors.Is(err, orders.ErrInsufficientBalance) {
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
 
 			return
 		}
-
-		if balance.Current.Sub(withdraw.Sum).LessThan(decimal.Zero) {
-			http.Error(w, "insufficient balance", http.StatusPaymentRequired)
+		if errors.Is(err, orders.ErrWithdrawalLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
 
 			return
 		}
+		if errors.Is(err, orders.ErrOrderExists) || errors.Is(err, orders.ErrOtherOrderExists) {
+			http.Error(w, err.Error(), http.StatusConflict)
 
-		err = ordersStore.Withdraw(requestContext, login, &withdraw)
+			return
+		}
+		if writeAmountError(w, err) {
+			return
+		}
 		if err != nil {
 			http.Error(
 				w,
@@ -151,36 +256,66 @@ func withdrawHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *ht
 	}
 }
 
-func getBalance(ctx context.Context, login string, ordersStore orders.Store) (*models.Balance, error) {
-	var (
-		balance   models.Balance
-		withdrawn decimal.Decimal
-		accrual   decimal.Decimal
-	)
+func transferHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
 
-	processedOrders, err := ordersStore.GetProcessedOrders(ctx, login)
-	if err != nil {
-		return nil, err
-	}
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
 
-	withdrawals, err := ordersStore.GetWithdrawals(ctx, login)
-	if err != nil {
-		return nil, err
-	}
+			return
+		}
 
-	for _, order := range processedOrders {
-		accrual = order.Accrual.Add(accrual)
-	}
+		var transfer models.Transfer
+		err = json.NewDecoder(r.Body).Decode(&transfer)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
 
-	for _, withdraw := range withdrawals {
-		withdrawn = withdraw.Sum.Add(withdrawn)
-		accrual = accrual.Sub(withdraw.Sum)
+			return
+		}
+
+		err = ordersStore.Transfer(requestContext, login, &transfer)
+		switch {
+		case writeAmountError(w, err):
+		case errors.Is(err, orders.ErrSelfTransfer):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, orders.ErrRecipientNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, orders.ErrInsufficientBalance):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+		case errors.Is(err, orders.ErrWithdrawalLimitExceeded):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		case errors.Is(err, orders.ErrTransferBelowMinimum),
+			errors.Is(err, orders.ErrTransferAboveMaximum),
+			errors.Is(err, orders.ErrTransferLimitExceeded):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't transfer points from %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
 	}
+}
 
-	balance = models.Balance{
-		Current:   accrual,
-		Withdrawn: withdrawn,
+// writeAmountError responds with the code of the amount policy violation,
+// it reports whether err was such a violation.
+func writeAmountError(w http.ResponseWriter, err error) bool {
+	var amountErr *models.AmountError
+	if !errors.As(err, &amountErr) {
+		return false
 	}
 
-	return &balance, nil
+	http.Error(w, fmt.Sprintf("%s: %s", amountErr.Code, amountErr.Message), http.StatusUnprocessableEntity)
+
+	return true
 }
@@ -1,15 +1,18 @@
 package handlers_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
 	"github.com/go-rfe/loyalty-system/internal/server/handlers"
 	"github.com/golang/mock/gomock"
@@ -27,43 +30,259 @@ type testBalance struct {
 	name       string
 	method     string
 	url        string
+	body       string
 	authHeader string
 	buildStubs func(store *mocks.MockStore)
 	want       wantBalance
 }
 
 func TestBalanceHandlers(t *testing.T) {
-	accrualOne := decimal.NewFromFloat(300.2)
-	accrualTwo := decimal.NewFromFloat(451.0)
-	processedOrders := []models.Order{
+	testOrders := []testBalance{
 		{
-			Accrual: &accrualOne,
+			name:       "Get Balance",
+			method:     http.MethodGet,
+			url:        "/api/user/balance",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"current\":600.8,\"withdrawn\":50.4,\"pending\":120,\"locked\":0,\"expiring\":200.8}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetBalance(gomock.Any(), "test").Return(&models.Balance{
+					Current:   decimal.NewFromFloat(600.8),
+					Withdrawn: decimal.NewFromFloat(50.4),
+					Pending:   decimal.NewFromInt(120),
+					Expiring:  decimal.NewFromFloat(200.8),
+				}, nil).Times(1)
+			},
 		},
 		{
-			Accrual: &accrualTwo,
+			name:       "Get Balance as of date",
+			method:     http.MethodGet,
+			url:        "/api/user/balance?as_of=2014-11-12T11:45:26.371Z",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"current\":300,\"withdrawn\":0,\"pending\":0,\"locked\":0,\"expiring\":0}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), "test", getDate()).Return(&models.Balance{
+					Current: decimal.NewFromInt(300),
+				}, nil).Times(1)
+			},
 		},
-	}
-	withdrawals := []models.Withdraw{
 		{
-			Sum: decimal.NewFromFloat(25.1),
+			name:       "Get Balance as of bad date",
+			method:     http.MethodGet,
+			url:        "/api/user/balance?as_of=yesterday",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetBalanceAsOf(gomock.Any(), "test", gomock.Any()).Times(0)
+			},
 		},
 		{
-			Sum: decimal.NewFromFloat(25.3),
+			name:       "Withdraw",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"2377225624\",\"sum\":751}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Withdraw(gomock.Any(), "test", &models.Withdraw{
+					Order: "2377225624",
+					Sum:   decimal.NewFromInt(751),
+				}).Return(nil).Times(1)
+			},
 		},
-	}
-	testOrders := []testBalance{
 		{
-			name:       "Get Balance",
-			method:     http.MethodGet,
-			url:        "/api/user/balance",
+			name:       "Withdraw to order longer than int64",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"12345678901234567890123459\",\"sum\":751}",
 			authHeader: authHeader,
 			want: wantBalance{
 				code: http.StatusOK,
-				data: "{\"current\":700.8,\"withdrawn\":50.4}\n",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().GetProcessedOrders(gomock.Any(), "test").Return(processedOrders, nil).Times(1)
-				store.EXPECT().GetWithdrawals(gomock.Any(), "test").Return(withdrawals, nil).Times(1)
+				store.EXPECT().Withdraw(gomock.Any(), "test", &models.Withdraw{
+					Order: "12345678901234567890123459",
+					Sum:   decimal.NewFromInt(751),
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Withdraw to existing long order",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"12345678901234567890123459\",\"sum\":751}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Withdraw(gomock.Any(), "test", gomock.Any()).Return(orders.ErrOtherOrderExists).Times(1)
+			},
+		},
+		{
+			name:       "Withdraw too precise amount",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"2377225624\",\"sum\":7.515}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Withdraw(gomock.Any(), "test", gomock.Any()).Return(models.ErrAmountTooPrecise).Times(1)
+			},
+		},
+		{
+			name:       "Withdraw insufficient balance",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"2377225624\",\"sum\":751}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusPaymentRequired,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Withdraw(gomock.Any(), "test", gomock.Any()).Return(orders.ErrInsufficientBalance).Times(1)
+			},
+		},
+		{
+			name:       "Withdraw over limit",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw",
+			body:       "{\"order\":\"2377225624\",\"sum\":751}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusTooManyRequests,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Withdraw(gomock.Any(), "test", gomock.Any()).
+					Return(fmt.Errorf("%w: DAILY count 3", orders.ErrWithdrawalLimitExceeded)).Times(1)
+			},
+		},
+		{
+			name:       "Authorize withdrawal",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw/authorize",
+			body:       "{\"order\":\"2377225624\",\"sum\":100}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"id\":7,\"order\":\"2377225624\",\"sum\":100,\"status\":\"AUTHORIZED\"," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\",\"expires_at\":\"2014-11-12T11:45:26.371Z\"}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().AuthorizeWithdraw(gomock.Any(), "test", &models.Withdraw{
+					Order: "2377225624",
+					Sum:   decimal.NewFromInt(100),
+				}).Return(&models.Hold{
+					ID:        7,
+					Order:     "2377225624",
+					Sum:       decimal.NewFromInt(100),
+					Status:    models.HoldAuthorized,
+					CreatedAt: getDate(),
+					ExpiresAt: getDate(),
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Capture withdrawal",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw/7/capture",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CaptureWithdraw(gomock.Any(), "test", int64(7)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Capture expired withdrawal",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw/7/capture",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CaptureWithdraw(gomock.Any(), "test", int64(7)).Return(orders.ErrHoldNotActive).Times(1)
+			},
+		},
+		{
+			name:       "Void unknown withdrawal",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/withdraw/8/void",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().VoidWithdraw(gomock.Any(), "test", int64(8)).Return(orders.ErrHoldNotFound).Times(1)
+			},
+		},
+		{
+			name:       "Transfer",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/transfer",
+			body:       "{\"login\":\"relative\",\"sum\":50}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Transfer(gomock.Any(), "test", &models.Transfer{
+					Login: "relative",
+					Sum:   decimal.NewFromInt(50),
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Transfer negative sum",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/transfer",
+			body:       "{\"login\":\"relative\",\"sum\":-50}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Transfer(gomock.Any(), "test", gomock.Any()).Return(models.ErrAmountNotPositive).Times(1)
+			},
+		},
+		{
+			name:       "Transfer to unknown user",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/transfer",
+			body:       "{\"login\":\"stranger\",\"sum\":50}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Transfer(gomock.Any(), "test", gomock.Any()).Return(orders.ErrRecipientNotFound).Times(1)
+			},
+		},
+		{
+			name:       "Transfer over withdrawal limit",
+			method:     http.MethodPost,
+			url:        "/api/user/balance/transfer",
+			body:       "{\"login\":\"friend\",\"sum\":50}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusTooManyRequests,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Transfer(gomock.Any(), "test", gomock.Any()).
+					Return(fmt.Errorf("%w: DAILY count 3", orders.ErrWithdrawalLimitExceeded)).Times(1)
 			},
 		},
 		{
@@ -91,7 +310,7 @@ func TestBalanceHandlers(t *testing.T) {
 
 	mux := chi.NewRouter()
 	store := getBalanceStore(t)
-	handlers.RegisterPrivateHandlers(mux, store, jwtToken)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
 
 	ts := httptest.NewServer(mux)
 	defer ts.Close()
@@ -107,7 +326,7 @@ func TestBalanceHandlers(t *testing.T) {
 func testBalanceRequest(t *testing.T, ts *httptest.Server, testData testBalance) {
 	t.Helper()
 
-	req, err := http.NewRequest(testData.method, ts.URL+testData.url, nil)
+	req, err := http.NewRequest(testData.method, ts.URL+testData.url, strings.NewReader(testData.body))
 	require.NoError(t, err)
 
 	req.Header.Set("Authorization", testData.authHeader)
@@ -118,10 +337,12 @@ func testBalanceRequest(t *testing.T, ts *httptest.Server, testData testBalance)
 	assert.Equal(t, testData.want.code, resp.StatusCode)
 
 	respBody, err := ioutil.ReadAll(resp.Body)
-	assert.JSONEq(t, testData.want.data, string(respBody))
-
 	require.NoError(t, err)
 
+	if testData.want.data != "" {
+		assert.JSONEq(t, testData.want.data, string(respBody))
+	}
+
 	err = resp.Body.Close()
 	if err != nil {
 		return
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+const (
+	defaultMaxBatchSize = 100
+	batchTimeout        = 10 * time.Second
+	csvNumberHeader     = "number"
+	// batchNumberBytes is the body size allowed per order number of the batch.
+	batchNumberBytes = 128
+)
+
+var (
+	ErrEmptyBatch    = errors.New("batch has no order numbers")
+	ErrBatchTooLarge = errors.New("batch is too large")
+	ErrBatchNotArray = errors.New("batch must be a JSON array of order numbers")
+)
+
+func (c Config) maxBatchSize() int {
+	if c.MaxBatchSize <= 0 {
+		return defaultMaxBatchSize
+	}
+
+	return c.MaxBatchSize
+}
+
+// createOrders uploads a JSON array or a CSV of order numbers and reports
+// the result for each of them.
+func createOrders(ordersStore orders.Store, validators models.OrderValidators, partnerKeys models.PartnerKeys,
+	maxBatchSize int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), batchTimeout)
+		defer requestCancel()
+
+		partner, ok := uploadPartner(w, r, partnerKeys)
+		if !ok {
+			return
+		}
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		numbers, err := readBatch(w, r, maxBatchSize)
+		if errors.Is(err, ErrBatchTooLarge) {
+			http.Error(
+				w,
+				fmt.Sprintf("%s: at most %d order numbers allowed", ErrBatchTooLarge, maxBatchSize),
+				http.StatusRequestEntityTooLarge,
+			)
+
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		results := make([]models.BatchResult, 0, len(numbers))
+		for _, number := range numbers {
+			attempt := models.UploadAttempt{Login: login, IP: clientIP(r), Number: number}
+			results = append(results, createBatchOrder(requestContext, ordersStore, &attempt, partner, validators))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&results, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func createBatchOrder(
+	ctx context.Context,
+	ordersStore orders.Store,
+	attempt *models.UploadAttempt,
+	partner string,
+	validators models.OrderValidators,
+) models.BatchResult {
+	number := attempt.Number
+	result := models.BatchResult{Number: number}
+
+	validationErr := validators.Validate(partner, number)
+	attempt.Valid = validationErr == nil
+
+	decision, err := ordersStore.ScreenUpload(ctx, attempt)
+	if err != nil {
+		log.Error().Err(err).Msgf("couldn't screen order %s upload", number)
+		result.Result = models.BatchFailed
+		result.Error = err.Error()
+
+		return result
+	}
+
+	switch fraudErr := fraudError(decision); {
+	case fraudErr != nil:
+		result.Result = models.BatchRejected
+		result.Error = fraudErr.Error()
+
+		return result
+	case validationErr != nil:
+		result.Result = models.BatchInvalid
+		if errors.Is(validationErr, models.ErrUnknownPartner) {
+			result.Error = validationErr.Error()
+		}
+
+		return result
+	}
+
+	err = ordersStore.CreateOrder(ctx, attempt.Login, number, partner)
+	if err == nil {
+		flagUpload(ctx, ordersStore, number, decision)
+	}
+
+	switch {
+	case errors.Is(err, orders.ErrOtherOrderExists):
+		result.Result = models.BatchOwnedByOther
+	case errors.Is(err, orders.ErrOrderExists):
+		result.Result = models.BatchAlreadyYours
+	case err != nil:
+		log.Error().Err(err).Msgf("couldn't create order %s", number)
+		result.Result = models.BatchFailed
+		result.Error = err.Error()
+	default:
+		result.Result = models.BatchAccepted
+	}
+
+	return result
+}
+
+// readBatch reads order numbers from the JSON array or from the first
+// column of CSV, the CSV header is optional. Reading stops as soon as the
+// batch gets longer than maxBatchSize, the body size is capped as well.
+func readBatch(w http.ResponseWriter, r *http.Request, maxBatchSize int) ([]string, error) {
+	maxBytes := int64(maxBatchSize+1) * batchNumberBytes
+	if r.ContentLength > maxBytes {
+		return nil, ErrBatchTooLarge
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxBytes)
+	numbers := make([]string, 0)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		csvReader := csv.NewReader(body)
+		csvReader.FieldsPerRecord = -1
+
+		for {
+			record, err := csvReader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			number := strings.TrimSpace(record[0])
+			if number == "" || len(numbers) == 0 && strings.EqualFold(number, csvNumberHeader) {
+				continue
+			}
+
+			numbers = append(numbers, number)
+			if len(numbers) > maxBatchSize {
+				return nil, ErrBatchTooLarge
+			}
+		}
+	} else {
+		var err error
+		if numbers, err = readJSONBatch(body, maxBatchSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(numbers) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	return numbers, nil
+}
+
+// readJSONBatch decodes the JSON array of order numbers one by one.
+func readJSONBatch(body io.Reader, maxBatchSize int) ([]string, error) {
+	numbers := make([]string, 0)
+	decoder := json.NewDecoder(body)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		if err == nil {
+			err = ErrBatchNotArray
+		}
+
+		return nil, err
+	}
+
+	for decoder.More() {
+		var number string
+		if err := decoder.Decode(&number); err != nil {
+			return nil, err
+		}
+
+		numbers = append(numbers, number)
+		if len(numbers) > maxBatchSize {
+			return nil, ErrBatchTooLarge
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return numbers, nil
+}
@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchHandlers(t *testing.T) {
+	tests := []testBalance{
+		{
+			name:       "Upload JSON batch",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			body:       "[\"267876232367723\",\"2377225624\",\"12345678903\",\"1111\"]",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "[{\"number\":\"267876232367723\",\"result\":\"ACCEPTED\"}," +
+					"{\"number\":\"2377225624\",\"result\":\"ALREADY_YOURS\"}," +
+					"{\"number\":\"12345678903\",\"result\":\"OWNED_BY_OTHER\"}," +
+					"{\"number\":\"1111\",\"result\":\"INVALID\"}]\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Return(nil).Times(1)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "2377225624", "").Return(orders.ErrOrderExists).Times(1)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "12345678903", "").Return(orders.ErrOtherOrderExists).Times(1)
+			},
+		},
+		{
+			name:       "Upload too large batch",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			body:       "[\"267876232367723\",\"2377225624\",\"12345678903\",\"79927398713\",\"1111\"]",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusRequestEntityTooLarge,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), "").Times(0)
+			},
+		},
+		{
+			name:   "Stop reading too large batch",
+			method: http.MethodPost,
+			url:    "/api/user/orders/batch",
+			body: "[\"267876232367723\",\"2377225624\",\"12345678903\",\"79927398713\",\"1111\"," +
+				"not even JSON",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusRequestEntityTooLarge,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), "").Times(0)
+			},
+		},
+		{
+			name:       "Upload oversized body",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			body:       "[\"" + strings.Repeat("1", 1<<20) + "\"]",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusRequestEntityTooLarge,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), "").Times(0)
+			},
+		},
+		{
+			name:       "Upload empty batch",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			body:       "[]",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), "").Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getOrdersStore(t)
+	store.EXPECT().ScreenUpload(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{MaxBatchSize: 4})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+
+	t.Run("Upload CSV batch", func(t *testing.T) {
+		store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Return(nil).Times(1)
+		store.EXPECT().CreateOrder(gomock.Any(), "test", "2377225624", "").Return(nil).Times(1)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/user/orders/batch",
+			strings.NewReader("number,amount\n267876232367723,10\n2377225624,20\n"))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Authorization", authHeader)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		respBody, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, "[{\"number\":\"267876232367723\",\"result\":\"ACCEPTED\"},"+
+			"{\"number\":\"2377225624\",\"result\":\"ACCEPTED\"}]", string(respBody))
+	})
+}
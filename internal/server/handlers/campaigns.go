@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+type campaignAction func(ctx context.Context, campaignID int64) (*models.CampaignReport, error)
+
+func AdminCampaignsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getCampaignsHandler(ordersStore))
+		r.Post("/", createCampaignHandler(ordersStore))
+		r.Get("/{campaign}/report", campaignHandler(ordersStore.GetCampaignReport))
+		r.Post("/{campaign}/reverse", campaignHandler(ordersStore.ReverseCampaign))
+	}
+}
+
+func getCampaignsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		campaigns, err := ordersStore.GetCampaigns(requestContext)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't get campaigns: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&campaigns, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func createCampaignHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		var campaign models.Campaign
+		err := json.NewDecoder(r.Body).Decode(&campaign)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := campaign.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.CreateCampaign(requestContext, &campaign)
+		switch {
+		case errors.Is(err, orders.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("couldn't create campaign: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = models.Encode(&campaign, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func campaignHandler(action campaignAction) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		campaignID, err := strconv.ParseInt(chi.URLParam(r, "campaign"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad campaign id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		report, err := action(requestContext, campaignID)
+		switch {
+		case errors.Is(err, orders.ErrCampaignNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't process campaign %d: %q", campaignID, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(report, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
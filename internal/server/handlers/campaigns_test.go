@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestCampaignHandlers(t *testing.T) {
+	multiplier := decimal.NewFromInt(2)
+
+	tests := []testAdmin{
+		{
+			name:   "Create weekend campaign",
+			method: http.MethodPost,
+			url:    "/api/admin/campaigns",
+			body: "{\"name\":\"Double points\",\"starts_at\":\"2014-11-12T11:45:26.371Z\"," +
+				"\"ends_at\":\"2014-11-14T11:45:26.371Z\",\"multiplier\":2}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusCreated,
+				data: "{\"id\":0,\"name\":\"Double points\",\"starts_at\":\"2014-11-12T11:45:26.371Z\"," +
+					"\"ends_at\":\"2014-11-14T11:45:26.371Z\",\"multiplier\":2}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateCampaign(gomock.Any(), &models.Campaign{
+					Name:       "Double points",
+					StartsAt:   getDate(),
+					EndsAt:     getDate().Add(48 * time.Hour),
+					Multiplier: &multiplier,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:   "Create campaign without boost",
+			method: http.MethodPost,
+			url:    "/api/admin/campaigns",
+			body: "{\"name\":\"Nothing\",\"starts_at\":\"2014-11-12T11:45:26.371Z\"," +
+				"\"ends_at\":\"2014-11-14T11:45:26.371Z\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateCampaign(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Reverse campaign",
+			method:     http.MethodPost,
+			url:        "/api/admin/campaigns/3/reverse",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "{\"campaign_id\":3,\"bonuses\":4,\"users\":2,\"total\":400,\"reversed\":350}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ReverseCampaign(gomock.Any(), int64(3)).Return(&models.CampaignReport{
+					CampaignID: 3,
+					Bonuses:    4,
+					Users:      2,
+					Total:      decimal.NewFromInt(400),
+					Reversed:   decimal.NewFromInt(350),
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Report of unknown campaign",
+			method:     http.MethodGet,
+			url:        "/api/admin/campaigns/9/report",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetCampaignReport(gomock.Any(), int64(9)).Return(nil, orders.ErrCampaignNotFound).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+func ClaimsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getClaimsHandler(ordersStore))
+		r.Post("/{claim}/contest", contestClaimHandler(ordersStore))
+	}
+}
+
+func AdminClaimsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", listClaimsHandler(ordersStore))
+		r.Post("/{claim}/decision", decideClaimHandler(ordersStore))
+	}
+}
+
+func createClaimHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var claim models.Claim
+		err = json.NewDecoder(r.Body).Decode(&claim)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		claim.Claimant = login
+		claim.Number = chi.URLParam(r, "number")
+
+		err = ordersStore.CreateClaim(requestContext, &claim)
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case errors.Is(err, orders.ErrClaimOwnOrder), errors.Is(err, orders.ErrClaimExists),
+			errors.Is(err, orders.ErrClaimDisputed):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't claim order %s for %s: %q", claim.Number, login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = models.Encode(&claim, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func getClaimsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		claims, err := ordersStore.GetClaims(requestContext, login)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get claims of %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&claims, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func contestClaimHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		claimID, err := strconv.ParseInt(chi.URLParam(r, "claim"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad claim id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		var contest models.ClaimContest
+		err = json.NewDecoder(r.Body).Decode(&contest)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := contest.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.ContestClaim(requestContext, login, claimID, contest.Comment)
+		writeClaimError(w, claimID, err)
+	}
+}
+
+func listClaimsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		status := r.URL.Query().Get("status")
+		if err := models.ValidateClaimStatus(status); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		claims, err := ordersStore.ListClaims(requestContext, status)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't get claims: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&claims, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func decideClaimHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		claimID, err := strconv.ParseInt(chi.URLParam(r, "claim"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad claim id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		var decision models.ClaimDecision
+		err = json.NewDecoder(r.Body).Decode(&decision)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		err = ordersStore.DecideClaim(requestContext, claimID, decision.Approve, operator)
+		writeClaimError(w, claimID, err)
+	}
+}
+
+// writeClaimError responds with the outcome of the claim update.
+func writeClaimError(w http.ResponseWriter, claimID int64, err error) {
+	switch {
+	case errors.Is(err, orders.ErrClaimNotFound), errors.Is(err, orders.ErrOrderNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, orders.ErrClaimClosed), errors.Is(err, orders.ErrClaimDisputed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(
+			w,
+			fmt.Sprintf("couldn't update claim %d: %q", claimID, err),
+			http.StatusInternalServerError,
+		)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+)
+
+func TestClaimsHandlers(t *testing.T) {
+	tests := []testBalance{
+		{
+			name:       "Claim order",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/claim",
+			body:       "{\"proof\":\"https://example.com/receipt.jpg\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusCreated,
+				data: "{\"id\":5,\"number\":\"267876232367723\",\"claimant\":\"test\"," +
+					"\"proof\":\"https://example.com/receipt.jpg\",\"status\":\"PENDING\"," +
+					"\"deadline\":\"2014-11-19T11:45:26.371Z\",\"created_at\":\"2014-11-12T11:45:26.371Z\"}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateClaim(gomock.Any(), &models.Claim{
+					Number:   "267876232367723",
+					Claimant: "test",
+					Proof:    "https://example.com/receipt.jpg",
+				}).DoAndReturn(func(_ interface{}, claim *models.Claim) error {
+					claim.ID = 5
+					claim.Status = models.ClaimPending
+					claim.CreatedAt = getDate()
+					claim.Deadline = getDate().Add(7 * 24 * time.Hour)
+
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:       "Claim own order",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/claim",
+			body:       "{}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateClaim(gomock.Any(), gomock.Any()).Return(orders.ErrClaimOwnOrder).Times(1)
+			},
+		},
+		{
+			name:       "Claim disputed order",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/claim",
+			body:       "{}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateClaim(gomock.Any(), gomock.Any()).Return(orders.ErrClaimDisputed).Times(1)
+			},
+		},
+		{
+			name:       "Contest claim",
+			method:     http.MethodPost,
+			url:        "/api/user/claims/5/contest",
+			body:       "{\"comment\":\"I have the receipt\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ContestClaim(gomock.Any(), "test", int64(5), "I have the receipt").Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Contest claim after deadline",
+			method:     http.MethodPost,
+			url:        "/api/user/claims/5/contest",
+			body:       "{\"comment\":\"I have the receipt\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ContestClaim(gomock.Any(), "test", int64(5), gomock.Any()).
+					Return(orders.ErrClaimClosed).Times(1)
+			},
+		},
+		{
+			name:       "Contest claim without comment",
+			method:     http.MethodPost,
+			url:        "/api/user/claims/5/contest",
+			body:       "{}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ContestClaim(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
+
+func TestAdminClaimsHandlers(t *testing.T) {
+	tests := []testAdmin{
+		{
+			name:       "List contested claims",
+			method:     http.MethodGet,
+			url:        "/api/admin/claims?status=CONTESTED",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "[]",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ListClaims(gomock.Any(), models.ClaimContested).Return([]models.Claim{}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Approve claim",
+			method:     http.MethodPost,
+			url:        "/api/admin/claims/5/decision",
+			body:       "{\"approve\":true}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideClaim(gomock.Any(), int64(5), true, "test").Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Reject unknown claim",
+			method:     http.MethodPost,
+			url:        "/api/admin/claims/6/decision",
+			body:       "{\"approve\":false}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideClaim(gomock.Any(), int64(6), false, "test").Return(orders.ErrClaimNotFound).Times(1)
+			},
+		},
+		{
+			name:       "Approve claim on disputed order",
+			method:     http.MethodPost,
+			url:        "/api/admin/claims/5/decision",
+			body:       "{\"approve\":true}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideClaim(gomock.Any(), int64(5), true, "test").Return(orders.ErrClaimDisputed).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+func DisputesHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getDisputesHandler(ordersStore))
+	}
+}
+
+func AdminDisputesHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", listDisputesHandler(ordersStore))
+		r.Post("/{dispute}/assign", assignDisputeHandler(ordersStore))
+		r.Post("/{dispute}/resolve", resolveDisputeHandler(ordersStore))
+	}
+}
+
+func openDisputeHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var dispute models.Dispute
+		err = json.NewDecoder(r.Body).Decode(&dispute)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		dispute.Login = login
+		dispute.Number = chi.URLParam(r, "number")
+
+		if err := dispute.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.OpenDispute(requestContext, &dispute)
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case errors.Is(err, orders.ErrDisputeExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't open dispute on order %s for %s: %q", dispute.Number, login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = models.Encode(&dispute, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func getDisputesHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		disputes, err := ordersStore.GetDisputes(requestContext, login)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get disputes of %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&disputes, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func listDisputesHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		status := r.URL.Query().Get("status")
+		if err := models.ValidateDisputeStatus(status); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		disputes, err := ordersStore.ListDisputes(requestContext, status)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't get disputes: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&disputes, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func assignDisputeHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		disputeID, err := strconv.ParseInt(chi.URLParam(r, "dispute"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad dispute id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		var assignment models.DisputeAssignment
+		err = json.NewDecoder(r.Body).Decode(&assignment)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		if assignment.Assignee == "" {
+			assignment.Assignee = operator
+		}
+
+		err = ordersStore.AssignDispute(requestContext, disputeID, assignment.Assignee, operator)
+		writeDisputeError(w, disputeID, err)
+	}
+}
+
+func resolveDisputeHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		disputeID, err := strconv.ParseInt(chi.URLParam(r, "dispute"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad dispute id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		var resolution models.DisputeResolution
+		err = json.NewDecoder(r.Body).Decode(&resolution)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		resolution.Operator = operator
+
+		if err := resolution.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.ResolveDispute(requestContext, disputeID, &resolution)
+		writeDisputeError(w, disputeID, err)
+	}
+}
+
+// writeDisputeError responds with the outcome of the dispute update.
+func writeDisputeError(w http.ResponseWriter, disputeID int64, err error) {
+	switch {
+	case errors.Is(err, orders.ErrDisputeNotFound), errors.Is(err, orders.ErrOrderNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, orders.ErrDisputeResolved), errors.Is(err, orders.ErrDisputeNotRepollable):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, orders.ErrInsufficientBalance):
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+	case err != nil:
+		http.Error(
+			w,
+			fmt.Sprintf("couldn't update dispute %d: %q", disputeID, err),
+			http.StatusInternalServerError,
+		)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
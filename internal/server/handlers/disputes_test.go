@@ -0,0 +1,262 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestDisputesHandlers(t *testing.T) {
+	tests := []testBalance{
+		{
+			name:       "Open dispute",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/dispute",
+			body:       "{\"comment\":\"accrual is too low\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusCreated,
+				data: "{\"id\":2,\"number\":\"267876232367723\",\"login\":\"test\",\"comment\":\"accrual is too low\"," +
+					"\"status\":\"OPEN\",\"created_at\":\"2014-11-12T11:45:26.371Z\"}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().OpenDispute(gomock.Any(), &models.Dispute{
+					Number:  "267876232367723",
+					Login:   "test",
+					Comment: "accrual is too low",
+				}).DoAndReturn(func(_ interface{}, dispute *models.Dispute) error {
+					dispute.ID = 2
+					dispute.Status = models.DisputeOpen
+					dispute.CreatedAt = getDate()
+
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:       "Open dispute without comment",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/dispute",
+			body:       "{}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().OpenDispute(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Open second dispute",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/267876232367723/dispute",
+			body:       "{\"comment\":\"still INVALID\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().OpenDispute(gomock.Any(), gomock.Any()).Return(orders.ErrDisputeExists).Times(1)
+			},
+		},
+		{
+			name:       "Get disputes",
+			method:     http.MethodGet,
+			url:        "/api/user/disputes",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "[{\"id\":2,\"number\":\"267876232367723\",\"login\":\"test\",\"comment\":\"accrual is too low\"," +
+					"\"status\":\"RESOLVED\",\"resolution\":{\"action\":\"ADJUST\",\"comment\":\"partner confirmed\"," +
+					"\"amount\":100,\"reason\":\"CORRECTION\",\"adjustment_id\":4,\"operator\":\"admin\"}," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\",\"resolved_at\":\"2014-11-12T11:45:26.371Z\"}]\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				amount := decimal.NewFromInt(100)
+				adjustmentID := int64(4)
+				resolvedAt := getDate()
+				store.EXPECT().GetDisputes(gomock.Any(), "test").Return([]models.Dispute{
+					{
+						ID:      2,
+						Number:  "267876232367723",
+						Login:   "test",
+						Comment: "accrual is too low",
+						Status:  models.DisputeResolved,
+						Resolution: &models.DisputeResolution{
+							Action:       models.ResolutionAdjust,
+							Comment:      "partner confirmed",
+							Amount:       &amount,
+							Reason:       models.ReasonCorrection,
+							AdjustmentID: &adjustmentID,
+							Operator:     "admin",
+						},
+						CreatedAt:  getDate(),
+						ResolvedAt: &resolvedAt,
+					},
+				}, nil).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
+
+func TestAdminDisputesHandlers(t *testing.T) {
+	tests := []testAdmin{
+		{
+			name:       "List open disputes",
+			method:     http.MethodGet,
+			url:        "/api/admin/disputes?status=OPEN",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "[]",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ListDisputes(gomock.Any(), models.DisputeOpen).Return([]models.Dispute{}, nil).Times(1)
+			},
+		},
+		{
+			name:       "List disputes with unknown status",
+			method:     http.MethodGet,
+			url:        "/api/admin/disputes?status=LOST",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ListDisputes(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Assign dispute to self",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/assign",
+			body:       "{}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().AssignDispute(gomock.Any(), int64(2), "test", "test").Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Assign resolved dispute",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/assign",
+			body:       "{\"assignee\":\"support\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().AssignDispute(gomock.Any(), int64(2), "support", "test").
+					Return(orders.ErrDisputeResolved).Times(1)
+			},
+		},
+		{
+			name:       "Resolve dispute with re-poll",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/resolve",
+			body:       "{\"action\":\"REPOLL\",\"comment\":\"provider outage\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ResolveDispute(gomock.Any(), int64(2), &models.DisputeResolution{
+					Action:   models.ResolutionRepoll,
+					Comment:  "provider outage",
+					Operator: "test",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Re-poll processed order",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/resolve",
+			body:       "{\"action\":\"REPOLL\",\"comment\":\"provider outage\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ResolveDispute(gomock.Any(), int64(2), gomock.Any()).
+					Return(orders.ErrDisputeNotRepollable).Times(1)
+			},
+		},
+		{
+			name:       "Resolve dispute with adjustment",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/resolve",
+			body:       "{\"action\":\"ADJUST\",\"comment\":\"partner confirmed\",\"amount\":100}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				amount := decimal.NewFromInt(100)
+				store.EXPECT().ResolveDispute(gomock.Any(), int64(2), &models.DisputeResolution{
+					Action:   models.ResolutionAdjust,
+					Comment:  "partner confirmed",
+					Amount:   &amount,
+					Reason:   models.ReasonCorrection,
+					Operator: "test",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Resolve dispute with adjustment without amount",
+			method:     http.MethodPost,
+			url:        "/api/admin/disputes/2/resolve",
+			body:       "{\"action\":\"ADJUST\",\"comment\":\"partner confirmed\"}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ResolveDispute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
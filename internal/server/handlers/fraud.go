@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+// fraudRetryAfter is the Retry-After of throttled uploads in seconds.
+const fraudRetryAfter = 60
+
+var (
+	ErrUploadRejected  = errors.New("order upload is rejected")
+	ErrUploadThrottled = errors.New("too many order uploads")
+)
+
+// fraudError returns the error of the decision which stops the upload,
+// nil for allowed and flagged uploads.
+func fraudError(decision *models.FraudDecision) error {
+	if decision == nil {
+		return nil
+	}
+
+	switch decision.Action {
+	case models.FraudReject:
+		return ErrUploadRejected
+	case models.FraudThrottle:
+		return ErrUploadThrottled
+	}
+
+	return nil
+}
+
+// writeFraudDecision responds to the rejected or throttled upload, it reports
+// whether the upload was stopped. Flagged uploads go on.
+func writeFraudDecision(w http.ResponseWriter, decision *models.FraudDecision) bool {
+	err := fraudError(decision)
+	switch {
+	case errors.Is(err, ErrUploadRejected):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrUploadThrottled):
+		w.Header().Set("Retry-After", strconv.Itoa(fraudRetryAfter))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// flagUpload marks the created order when the fraud rules flagged its upload.
+func flagUpload(ctx context.Context, ordersStore orders.Store, number string, decision *models.FraudDecision) {
+	if decision == nil || decision.Action != models.FraudFlag {
+		return
+	}
+
+	if err := ordersStore.FlagOrder(ctx, number, decision.Rule+": "+decision.Details); err != nil {
+		log.Error().Err(err).Msgf("Couldn't flag order %s", number)
+	}
+}
+
+// clientIP returns the address of the client without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
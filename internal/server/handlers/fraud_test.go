@@ -0,0 +1,107 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+)
+
+func TestFraudRules(t *testing.T) {
+	tests := []testBalance{
+		{
+			name:       "Throttled upload",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			body:       "267876232367723",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusTooManyRequests,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ScreenUpload(gomock.Any(), &models.UploadAttempt{
+					Login:  "test",
+					IP:     "127.0.0.1",
+					Number: "267876232367723",
+					Valid:  true,
+				}).Return(&models.FraudDecision{Rule: models.RuleUploadRate, Action: models.FraudThrottle}, nil).Times(1)
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Rejected invalid upload",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			body:       "1111",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusForbidden,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ScreenUpload(gomock.Any(), &models.UploadAttempt{
+					Login:  "test",
+					IP:     "127.0.0.1",
+					Number: "1111",
+					Valid:  false,
+				}).Return(&models.FraudDecision{Rule: models.RuleInvalidRatio, Action: models.FraudReject}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Flagged upload",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			body:       "267876232367723",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusAccepted,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ScreenUpload(gomock.Any(), gomock.Any()).Return(&models.FraudDecision{
+					Rule:    models.RuleSharedIP,
+					Action:  models.FraudFlag,
+					Details: "4 accounts",
+				}, nil).Times(1)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Return(nil).Times(1)
+				store.EXPECT().FlagOrder(gomock.Any(), "267876232367723", "SHARED_IP: 4 accounts").Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Rejected batch upload",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			body:       "[\"267876232367723\"]",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "[{\"number\":\"267876232367723\",\"result\":\"REJECTED\",\"error\":\"order upload is rejected\"}]",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().ScreenUpload(gomock.Any(), gomock.Any()).
+					Return(&models.FraudDecision{Rule: models.RuleUploadRate, Action: models.FraudReject}, nil).Times(1)
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 	"github.com/go-rfe/loyalty-system/internal/repository/users"
 )
@@ -16,6 +17,13 @@ const (
 
 var ErrInvalidToken = errors.New("invalid auth token")
 
+// Config tunes the private handlers, zero values fall back to the defaults.
+type Config struct {
+	MaxBatchSize int
+	Validators   models.OrderValidators
+	PartnerKeys  models.PartnerKeys
+}
+
 func RegisterPublicHandlers(mux *chi.Mux, userStore users.Store, auth *jwtauth.JWTAuth) {
 	mux.Group(func(r chi.Router) {
 		r.Route("/api/user/register", UserRegisterHandler(userStore, auth))
@@ -23,12 +31,35 @@ func RegisterPublicHandlers(mux *chi.Mux, userStore users.Store, auth *jwtauth.J
 	})
 }
 
-func RegisterPrivateHandlers(mux *chi.Mux, ordersStore orders.Store, auth *jwtauth.JWTAuth) {
+func RegisterPrivateHandlers(mux *chi.Mux, ordersStore orders.Store, auth *jwtauth.JWTAuth, cfg Config) {
 	mux.Group(func(r chi.Router) {
 		r.Use(jwtauth.Verifier(auth))
 		r.Use(jwtauth.Authenticator)
 
-		r.Route("/api/user/orders", OrdersHandler(ordersStore))
+		r.Route("/api/user/orders", OrdersHandler(ordersStore, cfg))
 		r.Route("/api/user/balance", BalanceHandler(ordersStore))
+		r.Route("/api/user/statement", StatementHandler(ordersStore))
+		r.Route("/api/user/tier", TierHandler(ordersStore))
+		r.Route("/api/user/referrals", ReferralsHandler(ordersStore))
+		r.Route("/api/user/rewards", RewardsHandler(ordersStore))
+		r.Route("/api/user/promo", PromoHandler(ordersStore))
+		r.Route("/api/user/disputes", DisputesHandler(ordersStore))
+		r.Route("/api/user/claims", ClaimsHandler(ordersStore))
+	})
+}
+
+func RegisterAdminHandlers(mux *chi.Mux, ordersStore orders.Store, auth *jwtauth.JWTAuth, admins []string) {
+	mux.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(auth))
+		r.Use(jwtauth.Authenticator)
+		r.Use(AdminOnly(admins))
+
+		r.Route("/api/admin/users", AdminUsersHandler(ordersStore))
+		r.Route("/api/admin/campaigns", AdminCampaignsHandler(ordersStore))
+		r.Route("/api/admin/rewards", AdminRewardsHandler(ordersStore))
+		r.Route("/api/admin/promo-batches", AdminPromoHandler(ordersStore))
+		r.Route("/api/admin/disputes", AdminDisputesHandler(ordersStore))
+		r.Route("/api/admin/claims", AdminClaimsHandler(ordersStore))
+		r.Route("/api/admin/reviews", AdminReviewsHandler(ordersStore))
 	})
 }
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+type holdAction func(ctx context.Context, login string, holdID int64) error
+
+func authorizeWithdrawHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var withdraw models.Withdraw
+		err = json.NewDecoder(r.Body).Decode(&withdraw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := models.Validate(withdraw.Order); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		hold, err := ordersStore.AuthorizeWithdraw(requestContext, login, &withdraw)
+		switch {
+		case writeAmountError(w, err):
+			return
+		case errors.Is(err, orders.ErrInsufficientBalance):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+
+			return
+		case errors.Is(err, orders.ErrWithdrawalLimitExceeded):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+
+			return
+		case errors.Is(err, orders.ErrOrderExists), errors.Is(err, orders.ErrOtherOrderExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't authorize withdrawal for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(hold, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func holdHandler(action holdAction) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		holdID, err := strconv.ParseInt(chi.URLParam(r, "hold"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad hold id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		err = action(requestContext, login, holdID)
+		switch {
+		case errors.Is(err, orders.ErrHoldNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, orders.ErrHoldNotActive), errors.Is(err, orders.ErrOtherOrderExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, orders.ErrInsufficientBalance):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't update withdrawal hold for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
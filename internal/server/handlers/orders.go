@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
@@ -14,18 +15,46 @@ import (
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 )
 
-func OrdersHandler(ordersStore orders.Store) func(r chi.Router) {
+func OrdersHandler(ordersStore orders.Store, cfg Config) func(r chi.Router) {
 	return func(r chi.Router) {
-		r.Post("/", createOrder(ordersStore))
+		r.Post("/", createOrder(ordersStore, cfg.Validators, cfg.PartnerKeys))
+		r.Post("/batch", createOrders(ordersStore, cfg.Validators, cfg.PartnerKeys, cfg.maxBatchSize()))
 		r.Get("/", getOrders(ordersStore))
+		r.Get("/{number}", getOrder(ordersStore))
+		r.Delete("/{number}", deleteOrder(ordersStore))
+		r.Post("/{number}/dispute", openDisputeHandler(ordersStore))
+		r.Post("/{number}/claim", createClaimHandler(ordersStore))
 	}
 }
 
-func createOrder(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+// partnerKeyHeader carries the key issued to the partner integration, the
+// partner whose validator checks the uploaded numbers is resolved from it.
+const partnerKeyHeader = "X-Partner-Key"
+
+// uploadPartner resolves the partner of the upload, the request with an
+// unknown key is refused.
+func uploadPartner(w http.ResponseWriter, r *http.Request, keys models.PartnerKeys) (string, bool) {
+	partner, err := keys.Partner(r.Header.Get(partnerKeyHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+
+		return "", false
+	}
+
+	return partner, true
+}
+
+func createOrder(ordersStore orders.Store, validators models.OrderValidators,
+	partnerKeys models.PartnerKeys) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
 		defer requestCancel()
 
+		partner, ok := uploadPartner(w, r, partnerKeys)
+		if !ok {
+			return
+		}
+
 		orderNumber, err := ioutil.ReadAll(r.Body)
 		if err != nil {
 			http.Error(
@@ -37,28 +66,54 @@ func createOrder(ordersStore orders.Store) func(w http.ResponseWriter, r *http.R
 			return
 		}
 
-		if err := models.Validate(string(orderNumber)); err != nil {
+		login, err := getLoginFromRequest(r)
+		if err != nil {
 			http.Error(
 				w,
-				fmt.Sprintf("Bad order number: %s (%q)", orderNumber, err),
-				http.StatusUnprocessableEntity,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
 			)
 
 			return
 		}
 
-		login, err := getLoginFromRequest(r)
+		validationErr := validators.Validate(partner, string(orderNumber))
+
+		decision, err := ordersStore.ScreenUpload(requestContext, &models.UploadAttempt{
+			Login:  login,
+			IP:     clientIP(r),
+			Number: string(orderNumber),
+			Valid:  validationErr == nil,
+		})
 		if err != nil {
 			http.Error(
 				w,
-				fmt.Sprintf("couldn't get user from token: %q", err),
+				fmt.Sprintf("couldn't screen order upload: %q", err),
 				http.StatusInternalServerError,
 			)
 
 			return
 		}
 
-		err = ordersStore.CreateOrder(requestContext, login, string(orderNumber))
+		if writeFraudDecision(w, decision) {
+			return
+		}
+
+		if validationErr != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("Bad order number: %s (%q)", orderNumber, validationErr),
+				http.StatusUnprocessableEntity,
+			)
+
+			return
+		}
+
+		err = ordersStore.CreateOrder(requestContext, login, string(orderNumber), partner)
+		if err == nil {
+			flagUpload(requestContext, ordersStore, string(orderNumber), decision)
+		}
+
 		switch {
 		case errors.Is(err, orders.ErrOtherOrderExists):
 			http.Error(
@@ -96,7 +151,14 @@ func getOrders(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Req
 			return
 		}
 
-		ordersSlice, err := ordersStore.GetOrders(requestContext, login)
+		filter, err := parseOrdersQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		ordersSlice, next, err := ordersStore.GetOrders(requestContext, login, filter)
 		if err != nil {
 			log.Error().Err(err).Msgf("couldn't get orders for %s", login)
 			http.Error(
@@ -108,6 +170,10 @@ func getOrders(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Req
 			return
 		}
 
+		if next != nil {
+			w.Header().Set(nextCursorHeader, next.Encode())
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		err = models.Encode(&ordersSlice, w)
 		if err != nil {
@@ -116,6 +182,118 @@ func getOrders(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Req
 	}
 }
 
+func getOrder(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		number := chi.URLParam(r, "number")
+
+		order, err := ordersStore.GetOrder(requestContext, login, number)
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get order %s for %s: %q", number, login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(order, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func deleteOrder(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		number := chi.URLParam(r, "number")
+
+		err = ordersStore.DeleteOrder(requestContext, login, number)
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, orders.ErrOrderNotCancellable), errors.Is(err, orders.ErrOrderHasOpenCase):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't cancel order %s for %s: %q", number, login, err),
+				http.StatusInternalServerError,
+			)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+// parseOrdersQuery reads page parameters along with status and sort ones,
+// sort is either uploaded_at or -uploaded_at for descending order.
+func parseOrdersQuery(r *http.Request) (models.OrdersFilter, error) {
+	var filter models.OrdersFilter
+
+	page, err := parsePageQuery(r)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Page = page
+	query := r.URL.Query()
+
+	for _, statuses := range query["status"] {
+		for _, status := range strings.Split(statuses, ",") {
+			filter.Statuses = append(filter.Statuses, strings.ToUpper(strings.TrimSpace(status)))
+		}
+	}
+
+	if err := filter.ValidateFields(); err != nil {
+		return filter, err
+	}
+
+	switch query.Get("sort") {
+	case "", "uploaded_at":
+	case "-uploaded_at":
+		filter.Descending = true
+	default:
+		return filter, fmt.Errorf("bad sort parameter: must be uploaded_at or -uploaded_at")
+	}
+
+	return filter, nil
+}
+
 func getLoginFromRequest(r *http.Request) (string, error) {
 	_, claims, err := jwtauth.FromContext(r.Context())
 	if err != nil {
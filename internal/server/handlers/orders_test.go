@@ -2,16 +2,19 @@ package handlers_test
 
 import (
 	"bytes"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
 	"github.com/go-rfe/loyalty-system/internal/server/handlers"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +36,7 @@ type testOrder struct {
 	url        string
 	order      string
 	authHeader string
+	partnerKey string
 	buildStubs func(store *mocks.MockStore)
 	want       wantOrders
 }
@@ -50,7 +54,21 @@ func TestOrdersHandlers(t *testing.T) {
 				data: "",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723")
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "")
+			},
+		},
+		{
+			name:       "OK Create order with long number",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			order:      "12345678901234567890123459",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusAccepted,
+				data: "",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "12345678901234567890123459", "")
 			},
 		},
 		{
@@ -64,7 +82,7 @@ func TestOrdersHandlers(t *testing.T) {
 				data: "",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723").Return(orders.ErrOrderExists)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Return(orders.ErrOrderExists)
 			},
 		},
 		{
@@ -78,7 +96,7 @@ func TestOrdersHandlers(t *testing.T) {
 				data: "",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().CreateOrder(gomock.Any(), "test", "1111").Times(0)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "1111", "").Times(0)
 			},
 		},
 		{
@@ -92,7 +110,118 @@ func TestOrdersHandlers(t *testing.T) {
 				data: "",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723").Return(orders.ErrOtherOrderExists)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Return(orders.ErrOtherOrderExists)
+			},
+		},
+		{
+			name:       "Get orders page",
+			method:     http.MethodGet,
+			url:        "/api/user/orders?status=new,processing&sort=-uploaded_at&limit=2",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetOrders(gomock.Any(), "test", models.OrdersFilter{
+					Page:       models.Page{Limit: 2},
+					Statuses:   []string{models.OrderNew, models.OrderProcessing},
+					Descending: true,
+				}).Return([]models.Order{}, nil, nil)
+			},
+		},
+		{
+			name:       "Get orders with unknown status",
+			method:     http.MethodGet,
+			url:        "/api/user/orders?status=LOST",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetOrders(gomock.Any(), "test", gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Get order with history",
+			method:     http.MethodGet,
+			url:        "/api/user/orders/267876232367723",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusOK,
+				data: "{\"number\":\"267876232367723\",\"status\":\"PROCESSED\",\"accrual\":500," +
+					"\"uploaded_at\":\"2014-11-12T11:45:26.371Z\",\"history\":[" +
+					"{\"new_status\":\"NEW\",\"source\":\"USER\",\"created_at\":\"2014-11-12T11:45:26.371Z\"}," +
+					"{\"old_status\":\"NEW\",\"new_status\":\"PROCESSED\",\"accrual\":500,\"source\":\"ACCRUAL\"," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\"}]}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				accrual := decimal.NewFromInt(500)
+				store.EXPECT().GetOrder(gomock.Any(), "test", "267876232367723").Return(&models.OrderDetails{
+					Order: models.Order{
+						Number:     "267876232367723",
+						Status:     models.OrderProcessed,
+						Accrual:    &accrual,
+						UploadedAt: getDate(),
+					},
+					History: []models.StatusChange{
+						{NewStatus: models.OrderNew, Source: models.HistorySourceUser, CreatedAt: getDate()},
+						{
+							OldStatus: models.OrderNew,
+							NewStatus: models.OrderProcessed,
+							Accrual:   &accrual,
+							Source:    models.HistorySourceAccrual,
+							CreatedAt: getDate(),
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:       "Get other user order",
+			method:     http.MethodGet,
+			url:        "/api/user/orders/2377225624",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetOrder(gomock.Any(), "test", "2377225624").Return(nil, orders.ErrOrderNotFound)
+			},
+		},
+		{
+			name:       "Cancel order",
+			method:     http.MethodDelete,
+			url:        "/api/user/orders/267876232367723",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DeleteOrder(gomock.Any(), "test", "267876232367723").Return(nil)
+			},
+		},
+		{
+			name:       "Cancel processed order",
+			method:     http.MethodDelete,
+			url:        "/api/user/orders/267876232367723",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DeleteOrder(gomock.Any(), "test", "267876232367723").Return(orders.ErrOrderNotCancellable)
+			},
+		},
+		{
+			name:       "Cancel disputed order",
+			method:     http.MethodDelete,
+			url:        "/api/user/orders/267876232367723",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DeleteOrder(gomock.Any(), "test", "267876232367723").Return(orders.ErrOrderHasOpenCase)
 			},
 		},
 		{
@@ -106,7 +235,7 @@ func TestOrdersHandlers(t *testing.T) {
 				data: "",
 			},
 			buildStubs: func(store *mocks.MockStore) {
-				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723").Times(0)
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Times(0)
 			},
 		},
 	}
@@ -115,7 +244,96 @@ func TestOrdersHandlers(t *testing.T) {
 
 	mux := chi.NewRouter()
 	store := getOrdersStore(t)
-	handlers.RegisterPrivateHandlers(mux, store, jwtToken)
+	store.EXPECT().ScreenUpload(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range testOrders {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testOrdersRequest(t, ts, tt)
+		})
+	}
+}
+
+func TestPartnerOrdersHandlers(t *testing.T) {
+	var (
+		validators  models.OrderValidators
+		partnerKeys models.PartnerKeys
+	)
+
+	require.NoError(t, validators.UnmarshalText([]byte("luhn;BANK=mod97")))
+	require.NoError(t, partnerKeys.UnmarshalText([]byte("bank-secret=BANK")))
+
+	testOrders := []testOrder{
+		{
+			name:       "Create partner order",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			order:      "123456789012345678901235",
+			authHeader: authHeader,
+			partnerKey: "bank-secret",
+			want: wantOrders{
+				code: http.StatusAccepted,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "123456789012345678901235", "BANK").Times(1)
+			},
+		},
+		{
+			name:       "Create order with partner name as key",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			order:      "123456789012345678901235",
+			authHeader: authHeader,
+			partnerKey: "BANK",
+			want: wantOrders{
+				code: http.StatusForbidden,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Upload batch with unknown key",
+			method:     http.MethodPost,
+			url:        "/api/user/orders/batch",
+			order:      "[\"123456789012345678901235\"]",
+			authHeader: authHeader,
+			partnerKey: "shop-secret",
+			want: wantOrders{
+				code: http.StatusForbidden,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Create order without key",
+			method:     http.MethodPost,
+			url:        "/api/user/orders",
+			order:      "267876232367723",
+			authHeader: authHeader,
+			want: wantOrders{
+				code: http.StatusAccepted,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateOrder(gomock.Any(), "test", "267876232367723", "").Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getOrdersStore(t)
+	store.EXPECT().ScreenUpload(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{
+		Validators:  validators,
+		PartnerKeys: partnerKeys,
+	})
 
 	ts := httptest.NewServer(mux)
 	defer ts.Close()
@@ -140,11 +358,22 @@ func testOrdersRequest(t *testing.T, ts *httptest.Server, testData testOrder) {
 	req.Header.Set("Content-Type", "text/plain")
 	req.Header.Set("Authorization", testData.authHeader)
 
+	if testData.partnerKey != "" {
+		req.Header.Set("X-Partner-Key", testData.partnerKey)
+	}
+
 	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer resp.Body.Close()
 
 	assert.Equal(t, testData.want.code, resp.StatusCode)
+
+	if testData.want.data != "" {
+		respBody, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, testData.want.data, string(respBody))
+	}
 }
 
 func getOrdersStore(t *testing.T) *mocks.MockStore {
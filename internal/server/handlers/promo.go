@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+type promoCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func PromoHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Post("/", redeemPromoCodeHandler(ordersStore))
+	}
+}
+
+func AdminPromoHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Post("/", createPromoBatchHandler(ordersStore))
+		r.Get("/{batch}/codes.csv", exportPromoBatchHandler(ordersStore))
+	}
+}
+
+func redeemPromoCodeHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var request promoCodeRequest
+		err = json.NewDecoder(r.Body).Decode(&request)
+		if err != nil || request.Code == "" {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		redemption, err := ordersStore.RedeemPromoCode(requestContext, login, clientIP(r), request.Code)
+		switch {
+		case errors.Is(err, orders.ErrPromoTooManyAttempts):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+
+			return
+		case errors.Is(err, orders.ErrPromoCodeInvalid):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case errors.Is(err, orders.ErrPromoCodeExpired):
+			http.Error(w, err.Error(), http.StatusGone)
+
+			return
+		case errors.Is(err, orders.ErrPromoCodeUsedUp), errors.Is(err, orders.ErrPromoCodeRedeemed):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't redeem promo code for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(redemption, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func createPromoBatchHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), batchTimeout)
+		defer requestCancel()
+
+		operator, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		batch := models.PromoBatch{MaxUses: 1}
+		err = json.NewDecoder(r.Body).Decode(&batch)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		batch.CreatedBy = operator
+
+		if err := batch.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		err = ordersStore.CreatePromoBatch(requestContext, &batch)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't create promo batch: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = models.Encode(&batch, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func exportPromoBatchHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		batchID, err := strconv.ParseInt(chi.URLParam(r, "batch"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad batch id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		batch, err := ordersStore.GetPromoBatch(requestContext, batchID)
+		switch {
+		case errors.Is(err, orders.ErrPromoBatchNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get promo batch %d: %q", batchID, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=promo-batch-%d.csv", batchID))
+
+		err = writePromoCodes(w, batch)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func writePromoCodes(w io.Writer, batch *models.PromoBatch) error {
+	var expiresAt string
+	if batch.ExpiresAt != nil {
+		expiresAt = batch.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"code", "amount", "max_uses", "uses", "expires_at"}); err != nil {
+		return err
+	}
+
+	for _, code := range batch.Codes {
+		err := csvWriter.Write([]string{
+			code.Code, batch.Amount.String(), strconv.Itoa(batch.MaxUses), strconv.Itoa(code.Uses), expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
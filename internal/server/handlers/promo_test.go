@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoHandlers(t *testing.T) {
+	tests := []testBalance{
+		{
+			name:       "Redeem promo code",
+			method:     http.MethodPost,
+			url:        "/api/user/promo",
+			body:       "{\"code\":\"GEZDGNBVGY3TQOJQ\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"code\":\"GEZDGNBVGY3TQOJQ\",\"amount\":250}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().RedeemPromoCode(gomock.Any(), "test", "127.0.0.1", "GEZDGNBVGY3TQOJQ").
+					Return(&models.PromoRedemption{
+						Code:   "GEZDGNBVGY3TQOJQ",
+						Amount: decimal.NewFromInt(250),
+					}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Redeem expired promo code",
+			method:     http.MethodPost,
+			url:        "/api/user/promo",
+			body:       "{\"code\":\"GEZDGNBVGY3TQOJQ\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusGone,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().RedeemPromoCode(gomock.Any(), "test", gomock.Any(), gomock.Any()).
+					Return(nil, orders.ErrPromoCodeExpired).Times(1)
+			},
+		},
+		{
+			name:       "Guess promo codes",
+			method:     http.MethodPost,
+			url:        "/api/user/promo",
+			body:       "{\"code\":\"AAAAAAAAAAAAAAAA\"}",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusTooManyRequests,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().RedeemPromoCode(gomock.Any(), "test", gomock.Any(), gomock.Any()).
+					Return(nil, orders.ErrPromoTooManyAttempts).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
+
+func TestAdminPromoHandlers(t *testing.T) {
+	expiresAt := getDate()
+
+	tests := []testAdmin{
+		{
+			name:       "Create promo batch",
+			method:     http.MethodPost,
+			url:        "/api/admin/promo-batches",
+			body:       "{\"name\":\"Flyers\",\"amount\":250,\"count\":2}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusCreated,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreatePromoBatch(gomock.Any(), &models.PromoBatch{
+					Name:      "Flyers",
+					Amount:    decimal.NewFromInt(250),
+					Count:     2,
+					MaxUses:   1,
+					CreatedBy: "test",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Create too large promo batch",
+			method:     http.MethodPost,
+			url:        "/api/admin/promo-batches",
+			body:       "{\"name\":\"Flyers\",\"amount\":250,\"count\":1000000}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreatePromoBatch(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+
+	t.Run("Export promo batch", func(t *testing.T) {
+		store.EXPECT().GetPromoBatch(gomock.Any(), int64(4)).Return(&models.PromoBatch{
+			ID:        4,
+			Amount:    decimal.NewFromInt(250),
+			MaxUses:   1,
+			ExpiresAt: &expiresAt,
+			Codes: []models.PromoCode{
+				{Code: "GEZDGNBVGY3TQOJQ", Uses: 1},
+				{Code: "MFRGGZDFMZTWQ2LK"},
+			},
+		}, nil).Times(1)
+
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/admin/promo-batches/4/codes.csv", nil)
+		require.NoError(t, err)
+
+		req.Header.Set("Authorization", authHeader)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		respBody, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "code,amount,max_uses,uses,expires_at\n"+
+			"GEZDGNBVGY3TQOJQ,250,1,1,2014-11-12T11:45:26Z\n"+
+			"MFRGGZDFMZTWQ2LK,250,1,0,2014-11-12T11:45:26Z\n", string(respBody))
+	})
+}
@@ -0,0 +1,59 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+)
+
+func TestReferralsHandlers(t *testing.T) {
+	rewardedAt := getDate()
+
+	tests := []testBalance{
+		{
+			name:       "Get referrals",
+			method:     http.MethodGet,
+			url:        "/api/user/referrals",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"code\":\"MFRGGZDF\",\"referrals\":[" +
+					"{\"login\":\"friend\",\"status\":\"REWARDED\",\"created_at\":\"2014-11-12T11:45:26.371Z\"," +
+					"\"rewarded_at\":\"2014-11-12T11:45:26.371Z\"}," +
+					"{\"login\":\"neighbour\",\"status\":\"PENDING\",\"created_at\":\"2014-11-12T11:45:26.371Z\"}]}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetReferrals(gomock.Any(), "test").Return(&models.Referrals{
+					Code: "MFRGGZDF",
+					Referrals: []models.Referral{
+						{Login: "friend", Status: models.ReferralRewarded, CreatedAt: getDate(), RewardedAt: &rewardedAt},
+						{Login: "neighbour", Status: models.ReferralPending, CreatedAt: getDate()},
+					},
+				}, nil).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
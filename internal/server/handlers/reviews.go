@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+func AdminReviewsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getReviewQueueHandler(ordersStore))
+		r.Post("/{number}/decision", decideReviewHandler(ordersStore))
+	}
+}
+
+func getReviewQueueHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		queue, err := ordersStore.GetReviewQueue(requestContext)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't get review queue: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&queue, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func decideReviewHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		reviewer, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		var decision models.ReviewDecision
+		err = json.NewDecoder(r.Body).Decode(&decision)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		number := chi.URLParam(r, "number")
+		err = ordersStore.DecideReview(requestContext, number, decision.Approve, reviewer)
+		switch {
+		case errors.Is(err, orders.ErrOrderNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, orders.ErrReviewNotFound):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't decide review of order %s: %q", number, err),
+				http.StatusInternalServerError,
+			)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAdminReviewsHandlers(t *testing.T) {
+	accrual := decimal.NewFromInt(5000)
+
+	tests := []testAdmin{
+		{
+			name:       "Get review queue",
+			method:     http.MethodGet,
+			url:        "/api/admin/reviews",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+				data: "[{\"number\":\"267876232367723\",\"login\":\"user\",\"accrual\":5000," +
+					"\"reason\":\"accrual 5000 exceeds 1000\",\"uploaded_at\":\"2014-11-12T11:45:26.371Z\"}]",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetReviewQueue(gomock.Any()).Return([]models.HeldAccrual{
+					{
+						Number:     "267876232367723",
+						Login:      "user",
+						Accrual:    &accrual,
+						Reason:     "accrual 5000 exceeds 1000",
+						UploadedAt: getDate(),
+					},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Approve held accrual",
+			method:     http.MethodPost,
+			url:        "/api/admin/reviews/267876232367723/decision",
+			body:       "{\"approve\":true}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideReview(gomock.Any(), "267876232367723", true, "test").Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Reject order not in review",
+			method:     http.MethodPost,
+			url:        "/api/admin/reviews/267876232367723/decision",
+			body:       "{\"approve\":false}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideReview(gomock.Any(), "267876232367723", false, "test").
+					Return(orders.ErrReviewNotFound).Times(1)
+			},
+		},
+		{
+			name:       "Decide unknown order",
+			method:     http.MethodPost,
+			url:        "/api/admin/reviews/12345678903/decision",
+			body:       "{\"approve\":true}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().DecideReview(gomock.Any(), "12345678903", true, "test").
+					Return(orders.ErrOrderNotFound).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+func RewardsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getRewardsHandler(ordersStore, false))
+		r.Get("/redemptions", getRedemptionsHandler(ordersStore))
+		r.Post("/{reward}/redeem", redeemHandler(ordersStore))
+	}
+}
+
+func AdminRewardsHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getRewardsHandler(ordersStore, true))
+		r.Post("/", saveRewardHandler(ordersStore))
+		r.Put("/{reward}", saveRewardHandler(ordersStore))
+	}
+}
+
+func getRewardsHandler(ordersStore orders.Store, all bool) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		rewards, err := ordersStore.GetRewards(requestContext, all)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't get rewards: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&rewards, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func getRedemptionsHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		redemptions, err := ordersStore.GetRedemptions(requestContext, login)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get redemptions of %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(&redemptions, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+func redeemHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		rewardID, err := strconv.ParseInt(chi.URLParam(r, "reward"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Bad reward id: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		redemption, err := ordersStore.Redeem(requestContext, login, rewardID)
+		switch {
+		case writeAmountError(w, err):
+			return
+		case errors.Is(err, orders.ErrRewardNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case errors.Is(err, orders.ErrRewardOutOfStock):
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		case errors.Is(err, orders.ErrInsufficientBalance):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+
+			return
+		case errors.Is(err, orders.ErrWithdrawalLimitExceeded):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+
+			return
+		case err != nil:
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't redeem reward %d for %s: %q", rewardID, login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(redemption, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+// saveRewardHandler creates the reward or replaces the one given in the path.
+func saveRewardHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		var reward models.Reward
+		err := json.NewDecoder(r.Body).Decode(&reward)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Cannot decode provided data: %q", err), http.StatusBadRequest)
+
+			return
+		}
+
+		if err := reward.ValidateFields(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		status := http.StatusCreated
+		if param := chi.URLParam(r, "reward"); param != "" {
+			reward.ID, err = strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Bad reward id: %q", err), http.StatusBadRequest)
+
+				return
+			}
+
+			status = http.StatusOK
+			err = ordersStore.UpdateReward(requestContext, &reward)
+		} else {
+			err = ordersStore.CreateReward(requestContext, &reward)
+		}
+
+		switch {
+		case errors.Is(err, orders.ErrRewardNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("couldn't save reward: %q", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		err = models.Encode(&reward, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
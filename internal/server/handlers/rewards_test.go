@@ -0,0 +1,221 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestRewardsHandlers(t *testing.T) {
+	stock := 5
+	active := true
+
+	tests := []testBalance{
+		{
+			name:       "Browse rewards",
+			method:     http.MethodGet,
+			url:        "/api/user/rewards",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "[{\"id\":1,\"name\":\"Coffee\",\"kind\":\"VOUCHER\",\"price\":150,\"stock\":5,\"active\":true}]\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetRewards(gomock.Any(), false).Return([]models.Reward{
+					{ID: 1, Name: "Coffee", Kind: models.RewardVoucher, Price: decimal.NewFromInt(150), Stock: &stock, Active: &active},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Redeem reward",
+			method:     http.MethodPost,
+			url:        "/api/user/rewards/1/redeem",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"id\":3,\"reward_id\":1,\"reward\":\"Coffee\",\"price\":150,\"code\":\"GEZDGNBVGY3TQOJQ\"," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\"}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Redeem(gomock.Any(), "test", int64(1)).Return(&models.Redemption{
+					ID:        3,
+					RewardID:  1,
+					Reward:    "Coffee",
+					Price:     decimal.NewFromInt(150),
+					Code:      "GEZDGNBVGY3TQOJQ",
+					CreatedAt: getDate(),
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Redeem sold out reward",
+			method:     http.MethodPost,
+			url:        "/api/user/rewards/1/redeem",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusConflict,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Redeem(gomock.Any(), "test", int64(1)).Return(nil, orders.ErrRewardOutOfStock).Times(1)
+			},
+		},
+		{
+			name:       "Redeem without enough points",
+			method:     http.MethodPost,
+			url:        "/api/user/rewards/1/redeem",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusPaymentRequired,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Redeem(gomock.Any(), "test", int64(1)).Return(nil, orders.ErrInsufficientBalance).Times(1)
+			},
+		},
+		{
+			name:       "Redeem over withdrawal limit",
+			method:     http.MethodPost,
+			url:        "/api/user/rewards/1/redeem",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusTooManyRequests,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().Redeem(gomock.Any(), "test", int64(1)).
+					Return(nil, orders.ErrWithdrawalLimitExceeded).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
+
+func TestAdminRewardsHandlers(t *testing.T) {
+	active := true
+
+	tests := []testAdmin{
+		{
+			name:       "Create reward",
+			method:     http.MethodPost,
+			url:        "/api/admin/rewards",
+			body:       "{\"name\":\"10% off\",\"kind\":\"DISCOUNT\",\"price\":500,\"active\":true}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusCreated,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateReward(gomock.Any(), &models.Reward{
+					Name:   "10% off",
+					Kind:   models.RewardDiscount,
+					Price:  decimal.NewFromInt(500),
+					Active: &active,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Create reward without active flag",
+			method:     http.MethodPost,
+			url:        "/api/admin/rewards",
+			body:       "{\"name\":\"10% off\",\"kind\":\"DISCOUNT\",\"price\":500}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusCreated,
+				data: "{\"id\":3,\"name\":\"10% off\",\"kind\":\"DISCOUNT\",\"price\":500,\"active\":true}",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateReward(gomock.Any(), &models.Reward{
+					Name:  "10% off",
+					Kind:  models.RewardDiscount,
+					Price: decimal.NewFromInt(500),
+				}).DoAndReturn(func(_ interface{}, reward *models.Reward) error {
+					reward.ID = 3
+					reward.Active = &active
+
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:       "Update reward keeping active flag",
+			method:     http.MethodPut,
+			url:        "/api/admin/rewards/3",
+			body:       "{\"name\":\"10% off\",\"kind\":\"DISCOUNT\",\"price\":500}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusOK,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().UpdateReward(gomock.Any(), &models.Reward{
+					ID:    3,
+					Name:  "10% off",
+					Kind:  models.RewardDiscount,
+					Price: decimal.NewFromInt(500),
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:       "Update unknown reward",
+			method:     http.MethodPut,
+			url:        "/api/admin/rewards/7",
+			body:       "{\"name\":\"10% off\",\"kind\":\"DISCOUNT\",\"price\":500}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().UpdateReward(gomock.Any(), gomock.Any()).Return(orders.ErrRewardNotFound).Times(1)
+			},
+		},
+		{
+			name:       "Create free reward",
+			method:     http.MethodPost,
+			url:        "/api/admin/rewards",
+			body:       "{\"name\":\"Gift\",\"kind\":\"ITEM\",\"price\":0}",
+			authHeader: authHeader,
+			want: wantAdmin{
+				code: http.StatusUnprocessableEntity,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().CreateReward(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterAdminHandlers(mux, store, jwtToken, []string{"test"})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testAdminRequest(t, ts, tt)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+	nextCursorHeader = "X-Next-Cursor"
+)
+
+func StatementHandler(ordersStore orders.Store) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/", getStatementHandler(ordersStore))
+	}
+}
+
+func getStatementHandler(ordersStore orders.Store) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestContext, requestCancel := context.WithTimeout(r.Context(), requestTimeout)
+		defer requestCancel()
+
+		login, err := getLoginFromRequest(r)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get user from token: %q", err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		page, err := parsePageQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		lines, next, err := ordersStore.GetStatement(requestContext, login, page)
+		if err != nil {
+			http.Error(
+				w,
+				fmt.Sprintf("couldn't get statement for %s: %q", login, err),
+				http.StatusInternalServerError,
+			)
+
+			return
+		}
+
+		if next != nil {
+			w.Header().Set(nextCursorHeader, next.Encode())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = models.Encode(lines, w)
+		if err != nil {
+			log.Error().Err(err).Msg("Cannot send request")
+		}
+	}
+}
+
+// parsePageQuery reads from, to, cursor and limit query parameters.
+func parsePageQuery(r *http.Request) (models.Page, error) {
+	page := models.Page{Limit: defaultPageLimit}
+	query := r.URL.Query()
+
+	for param, value := range map[string]*time.Time{"from": &page.From, "to": &page.To} {
+		if query.Get(param) == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, query.Get(param))
+		if err != nil {
+			return page, fmt.Errorf("bad %s parameter: %w", param, err)
+		}
+
+		*value = parsed
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		decoded, err := models.DecodeCursor(cursor)
+		if err != nil {
+			return page, err
+		}
+
+		page.After = decoded
+	}
+
+	if query.Get("limit") != "" {
+		parsed, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || parsed <= 0 || parsed > maxPageLimit {
+			return page, fmt.Errorf("bad limit parameter: must be between 1 and %d", maxPageLimit)
+		}
+
+		page.Limit = parsed
+	}
+
+	return page, nil
+}
@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wantStatement struct {
+	code   int
+	data   string
+	cursor string
+}
+
+type testStatement struct {
+	name       string
+	url        string
+	buildStubs func(store *mocks.MockStore)
+	want       wantStatement
+}
+
+func TestStatementHandlers(t *testing.T) {
+	next := &models.Cursor{At: getDate(), Source: 1, Key: "2377225624"}
+	from := time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []testStatement{
+		{
+			name: "Get statement",
+			url:  "/api/user/statement?from=2014-11-01T00:00:00Z&limit=2",
+			want: wantStatement{
+				code: http.StatusOK,
+				data: "[{\"kind\":\"ACCRUAL\",\"amount\":500,\"reference\":\"9278923470\",\"balance\":500," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\"}," +
+					"{\"kind\":\"WITHDRAWAL\",\"amount\":-100,\"reference\":\"2377225624\",\"balance\":400," +
+					"\"created_at\":\"2014-11-12T11:45:26.371Z\"}]\n",
+				cursor: next.Encode(),
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetStatement(gomock.Any(), "test", models.Page{From: from, Limit: 2}).Return(
+					[]models.StatementLine{
+						{
+							Kind:      models.LedgerAccrual,
+							Amount:    decimal.NewFromInt(500),
+							Reference: "9278923470",
+							Balance:   decimal.NewFromInt(500),
+							CreatedAt: getDate(),
+						},
+						{
+							Kind:      models.LedgerWithdrawal,
+							Amount:    decimal.NewFromInt(-100),
+							Reference: "2377225624",
+							Balance:   decimal.NewFromInt(400),
+							CreatedAt: getDate(),
+						},
+					}, next, nil).Times(1)
+			},
+		},
+		{
+			name: "Get statement next page",
+			url:  "/api/user/statement?cursor=" + next.Encode(),
+			want: wantStatement{
+				code: http.StatusOK,
+				data: "[]\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetStatement(gomock.Any(), "test", models.Page{After: next, Limit: 50}).Return(
+					[]models.StatementLine{}, nil, nil).Times(1)
+			},
+		},
+		{
+			name: "Bad cursor",
+			url:  "/api/user/statement?cursor=garbage",
+			want: wantStatement{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetStatement(gomock.Any(), "test", gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Bad limit",
+			url:  "/api/user/statement?limit=100500",
+			want: wantStatement{
+				code: http.StatusBadRequest,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetStatement(gomock.Any(), "test", gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.url, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", authHeader)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.want.code, resp.StatusCode)
+			assert.Equal(t, tt.want.cursor, resp.Header.Get("X-Next-Cursor"))
+
+			if tt.want.data != "" {
+				respBody, err := ioutil.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.want.data, string(respBody))
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-rfe/loyalty-system/internal/models"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders"
+	"github.com/go-rfe/loyalty-system/internal/repository/orders/mocks"
+	"github.com/go-rfe/loyalty-system/internal/server/handlers"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestTierHandlers(t *testing.T) {
+	nextThreshold := decimal.NewFromInt(5000)
+	remaining := decimal.NewFromInt(3800)
+
+	tests := []testBalance{
+		{
+			name:       "Get tier",
+			method:     http.MethodGet,
+			url:        "/api/user/tier",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusOK,
+				data: "{\"tier\":\"SILVER\",\"multiplier\":1.25,\"accrued\":1200," +
+					"\"next_tier\":\"GOLD\",\"next_threshold\":5000,\"remaining\":3800}\n",
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetTier(gomock.Any(), "test").Return(&models.TierStatus{
+					Tier:          "SILVER",
+					Multiplier:    decimal.NewFromFloat(1.25),
+					Accrued:       decimal.NewFromInt(1200),
+					NextTier:      "GOLD",
+					NextThreshold: &nextThreshold,
+					Remaining:     &remaining,
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Tiers disabled",
+			method:     http.MethodGet,
+			url:        "/api/user/tier",
+			authHeader: authHeader,
+			want: wantBalance{
+				code: http.StatusNotFound,
+			},
+			buildStubs: func(store *mocks.MockStore) {
+				store.EXPECT().GetTier(gomock.Any(), "test").Return(nil, orders.ErrTiersDisabled).Times(1)
+			},
+		},
+	}
+
+	jwtToken := jwtauth.New("HS256", []byte("test"), []byte("test"))
+
+	mux := chi.NewRouter()
+	store := getBalanceStore(t)
+	handlers.RegisterPrivateHandlers(mux, store, jwtToken, handlers.Config{})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStubs(store)
+			testBalanceRequest(t, ts, tt)
+		})
+	}
+}
@@ -53,7 +53,7 @@ func userRegisterHandler(store users.Store, auth *jwtauth.JWTAuth) func(w http.R
 			return
 		}
 
-		err = store.CreateUser(requestContext, user.Login, user.Password)
+		err = store.CreateUser(requestContext, user.Login, user.Password, user.ReferralCode, clientIP(r))
 		if errors.Is(err, users.ErrUserExists) {
 			http.Error(
 				w,
@@ -63,6 +63,13 @@ func userRegisterHandler(store users.Store, auth *jwtauth.JWTAuth) func(w http.R
 
 			return
 		}
+		if errors.Is(err, users.ErrInvalidReferralCode) ||
+			errors.Is(err, users.ErrSelfReferral) ||
+			errors.Is(err, users.ErrReferralLimitExceeded) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
 		if err != nil {
 			http.Error(
 				w,
@@ -24,7 +24,12 @@ func (s *LoyaltyServer) startListener() {
 	mux.Use(compressor.Handler)
 
 	handlers.RegisterPublicHandlers(mux, s.Cfg.UserStore, s.AuthToken())
-	handlers.RegisterPrivateHandlers(mux, s.Cfg.OrdersStore, s.AuthToken())
+	handlers.RegisterPrivateHandlers(mux, s.Cfg.OrdersStore, s.AuthToken(), handlers.Config{
+		MaxBatchSize: s.Cfg.MaxBatchSize,
+		Validators:   s.Cfg.OrderValidators,
+		PartnerKeys:  s.Cfg.PartnerKeys,
+	})
+	handlers.RegisterAdminHandlers(mux, s.Cfg.OrdersStore, s.AuthToken(), s.Cfg.AdminLogins)
 
 	httpServer := &http.Server{
 		Addr:    s.Cfg.ServerAddress,
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,16 +12,74 @@ import (
 
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 	"github.com/go-rfe/loyalty-system/internal/repository/users"
+	"github.com/shopspring/decimal"
 )
 
+var ErrInvalidHoldTTL = errors.New("withdraw hold TTL must be positive")
+
 type Config struct {
 	ServerAddress  string        `env:"RUN_ADDRESS"`
 	DatabaseURI    string        `env:"DATABASE_URI"`
 	Secret         []byte        `env:"SECRET"`
 	AccrualAddress string        `env:"ACCRUAL_SYSTEM_ADDRESS"`
 	PollInterval   time.Duration `env:"POLL_INTERVAL" envDefault:"10s"`
+	PointsTTL      time.Duration `env:"POINTS_TTL"`
+	ExpiryWarning  time.Duration `env:"POINTS_EXPIRY_WARNING" envDefault:"720h"`
+	ExpiryInterval time.Duration `env:"EXPIRY_INTERVAL" envDefault:"1h"`
+	HoldTTL        time.Duration `env:"WITHDRAW_HOLD_TTL" envDefault:"15m"`
+
+	TransferMin      decimal.Decimal `env:"TRANSFER_MIN"`
+	TransferMax      decimal.Decimal `env:"TRANSFER_MAX"`
+	TransferDailyMax decimal.Decimal `env:"TRANSFER_DAILY_MAX"`
+
+	AmountMin               decimal.Decimal `env:"AMOUNT_MIN"`
+	AmountMaxDecimalPlaces  int32           `env:"AMOUNT_MAX_DECIMAL_PLACES" envDefault:"2"`
+	AmountMaxPerTransaction decimal.Decimal `env:"AMOUNT_MAX_PER_TRANSACTION"`
+	AmountMaxDaily          decimal.Decimal `env:"AMOUNT_MAX_DAILY"`
+
+	WithdrawLimitDailyCount    int             `env:"WITHDRAW_LIMIT_DAILY_COUNT"`
+	WithdrawLimitDailyAmount   decimal.Decimal `env:"WITHDRAW_LIMIT_DAILY_AMOUNT"`
+	WithdrawLimitWeeklyCount   int             `env:"WITHDRAW_LIMIT_WEEKLY_COUNT"`
+	WithdrawLimitWeeklyAmount  decimal.Decimal `env:"WITHDRAW_LIMIT_WEEKLY_AMOUNT"`
+	WithdrawLimitMonthlyCount  int             `env:"WITHDRAW_LIMIT_MONTHLY_COUNT"`
+	WithdrawLimitMonthlyAmount decimal.Decimal `env:"WITHDRAW_LIMIT_MONTHLY_AMOUNT"`
+
+	Tiers          models.Tiers  `env:"TIERS"`
+	TierWindow     time.Duration `env:"TIER_WINDOW" envDefault:"8760h"`
+	TierInactivity time.Duration `env:"TIER_INACTIVITY" envDefault:"2160h"`
+
+	ReferralLimit         int             `env:"REFERRAL_LIMIT" envDefault:"50"`
+	ReferralReferrerBonus decimal.Decimal `env:"REFERRAL_REFERRER_BONUS"`
+	ReferralRefereeBonus  decimal.Decimal `env:"REFERRAL_REFEREE_BONUS"`
+
+	PromoMaxFailures   int           `env:"PROMO_MAX_FAILURES" envDefault:"5"`
+	PromoMaxIPFailures int           `env:"PROMO_MAX_IP_FAILURES" envDefault:"20"`
+	PromoFailureWindow time.Duration `env:"PROMO_FAILURE_WINDOW" envDefault:"1h"`
+
+	ClaimWindow time.Duration `env:"CLAIM_CONTEST_WINDOW" envDefault:"168h"`
+
+	FraudWindow             time.Duration   `env:"FRAUD_WINDOW" envDefault:"1h"`
+	FraudMaxUploads         int             `env:"FRAUD_MAX_UPLOADS"`
+	FraudUploadsAction      string          `env:"FRAUD_UPLOADS_ACTION" envDefault:"THROTTLE"`
+	FraudInvalidRatio       decimal.Decimal `env:"FRAUD_INVALID_RATIO"`
+	FraudInvalidMinAttempts int             `env:"FRAUD_INVALID_MIN_ATTEMPTS" envDefault:"10"`
+	FraudInvalidAction      string          `env:"FRAUD_INVALID_ACTION" envDefault:"REJECT"`
+	FraudMaxAccountsPerIP   int             `env:"FRAUD_MAX_ACCOUNTS_PER_IP"`
+	FraudAccountsAction     string          `env:"FRAUD_ACCOUNTS_ACTION" envDefault:"FLAG"`
+
+	ReviewMaxAccrual decimal.Decimal `env:"REVIEW_MAX_ACCRUAL"`
+	ReviewFlagged    bool            `env:"REVIEW_FLAGGED" envDefault:"true"`
+
+	AccrualMaturation models.MaturationPeriods `env:"ACCRUAL_MATURATION"`
+
+	MaxBatchSize    int                    `env:"ORDERS_MAX_BATCH_SIZE" envDefault:"100"`
+	OrderValidators models.OrderValidators `env:"ORDER_VALIDATORS"`
+	PartnerKeys     models.PartnerKeys     `env:"PARTNER_KEYS"`
+
+	AdminLogins []string `env:"ADMIN_LOGINS" envSeparator:","`
 
 	LogLevel string `env:"LOG_LEVEL"`
 
@@ -29,6 +89,15 @@ type Config struct {
 	jwtToken *jwtauth.JWTAuth
 }
 
+// ValidateFields checks the settings which have no meaningful zero value.
+func (c *Config) ValidateFields() error {
+	if c.HoldTTL <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidHoldTTL, c.HoldTTL)
+	}
+
+	return nil
+}
+
 type LoyaltyServer struct {
 	Cfg      *Config
 	context  context.Context
@@ -55,11 +124,19 @@ func (s *LoyaltyServer) Start(ctx context.Context) {
 	pollContext, cancelPoller := context.WithCancel(ctx)
 	go pollWorker.Run(pollContext, s.Cfg.OrdersStore)
 
+	expiryWorker := ExpiryWorker{Cfg: ExpiryConfig{
+		ExpiryInterval: s.Cfg.ExpiryInterval,
+	}}
+
+	expiryContext, cancelExpiry := context.WithCancel(ctx)
+	go expiryWorker.Run(expiryContext, s.Cfg.OrdersStore)
+
 	go s.startListener()
 	log.Info().Msgf("Start listener on %s", s.Cfg.ServerAddress)
 
 	log.Info().Msgf("%s signal received, graceful shutdown the server", <-getSignalChannel())
 	cancelPoller()
+	cancelExpiry()
 	s.stopListener()
 
 	if err := closeUsersStore(); err != nil {
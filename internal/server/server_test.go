@@ -0,0 +1,41 @@
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"github.com/go-rfe/loyalty-system/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHoldTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{name: "Default", wantErr: nil},
+		{name: "Custom", value: "5m", wantErr: nil},
+		{name: "Zero", value: "0", wantErr: server.ErrInvalidHoldTTL},
+		{name: "Negative", value: "-1m", wantErr: server.ErrInvalidHoldTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				t.Setenv("WITHDRAW_HOLD_TTL", tt.value)
+			}
+
+			var cfg server.Config
+			require.NoError(t, env.Parse(&cfg))
+
+			assert.ErrorIs(t, cfg.ValidateFields(), tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Positive(t, cfg.HoldTTL)
+				assert.LessOrEqual(t, cfg.HoldTTL, 15*time.Minute)
+			}
+		})
+	}
+}
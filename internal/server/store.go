@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/go-rfe/logging/log"
+	"github.com/go-rfe/loyalty-system/internal/models"
 	"github.com/go-rfe/loyalty-system/internal/repository/orders"
 
 	"github.com/go-rfe/loyalty-system/internal/repository/users"
@@ -19,13 +20,73 @@ func initStore(config *Config) (func() error, func() error) {
 		log.Fatal().Err(err).Msg("Couldn't create database connection")
 	}
 
-	userStore := users.NewDBStore(conn)
+	userStore := users.NewDBStore(conn, users.Config{ReferralLimit: config.ReferralLimit})
 	config.UserStore = userStore
 	log.Info().Msg("Using Database for user storage")
 
-	ordersStore := orders.NewDBStore(conn)
+	ordersStore := orders.NewDBStore(conn, config.OrdersConfig())
 	config.OrdersStore = ordersStore
 	log.Info().Msg("Using Database for orders storage")
 
 	return userStore.Close, ordersStore.Close
 }
+
+// FraudRules returns the velocity rules of order uploads.
+func (c *Config) FraudRules() models.FraudRules {
+	return models.FraudRules{
+		Window:             c.FraudWindow,
+		MaxUploads:         c.FraudMaxUploads,
+		UploadsAction:      c.FraudUploadsAction,
+		InvalidRatio:       c.FraudInvalidRatio,
+		InvalidMinAttempts: c.FraudInvalidMinAttempts,
+		InvalidAction:      c.FraudInvalidAction,
+		MaxAccountsPerIP:   c.FraudMaxAccountsPerIP,
+		AccountsAction:     c.FraudAccountsAction,
+	}
+}
+
+// OrdersConfig returns the part of the config used by orders store.
+func (c *Config) OrdersConfig() orders.Config {
+	return orders.Config{
+		PointsTTL:     c.PointsTTL,
+		ExpiryWarning: c.ExpiryWarning,
+		HoldTTL:       c.HoldTTL,
+		Transfer: orders.TransferLimits{
+			Min:      c.TransferMin,
+			Max:      c.TransferMax,
+			DailyMax: c.TransferDailyMax,
+		},
+		Amount: models.AmountPolicy{
+			Min:               c.AmountMin,
+			MaxDecimalPlaces:  c.AmountMaxDecimalPlaces,
+			MaxPerTransaction: c.AmountMaxPerTransaction,
+			MaxDaily:          c.AmountMaxDaily,
+		},
+		Tier: orders.TierConfig{
+			Tiers:      c.Tiers,
+			Window:     c.TierWindow,
+			Inactivity: c.TierInactivity,
+		},
+		Referral: orders.ReferralBonus{
+			Referrer: c.ReferralReferrerBonus,
+			Referee:  c.ReferralRefereeBonus,
+		},
+		Promo: orders.PromoAttempts{
+			MaxFailures:   c.PromoMaxFailures,
+			MaxIPFailures: c.PromoMaxIPFailures,
+			Window:        c.PromoFailureWindow,
+		},
+		ClaimWindow: c.ClaimWindow,
+		Fraud:       c.FraudRules(),
+		Review: models.ReviewPolicy{
+			MaxAccrual: c.ReviewMaxAccrual,
+			Flagged:    c.ReviewFlagged,
+		},
+		Maturation: c.AccrualMaturation,
+		WithdrawalLimits: []models.WithdrawalLimit{
+			{Period: models.LimitDaily, MaxCount: c.WithdrawLimitDailyCount, MaxAmount: c.WithdrawLimitDailyAmount},
+			{Period: models.LimitWeekly, MaxCount: c.WithdrawLimitWeeklyCount, MaxAmount: c.WithdrawLimitWeeklyAmount},
+			{Period: models.LimitMonthly, MaxCount: c.WithdrawLimitMonthlyCount, MaxAmount: c.WithdrawLimitMonthlyAmount},
+		},
+	}
+}